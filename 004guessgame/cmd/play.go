@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/samnart1/GoLang-Projects/004guessgame/internal/bot"
 	"github.com/samnart1/GoLang-Projects/004guessgame/internal/game"
 	"github.com/samnart1/GoLang-Projects/004guessgame/internal/ui"
 	"github.com/spf13/cobra"
@@ -12,6 +14,7 @@ var (
 	difficulty	string
 	timeLimit	int
 	hints		bool
+	botName		string
 )
 
 var playCmd = &cobra.Command{
@@ -25,6 +28,7 @@ func init() {
 	playCmd.Flags().StringVarP(&difficulty, "difficulty", "d", "medium", "Game difficulty (easy, medium, hard, custom)")
 	playCmd.Flags().IntVarP(&timeLimit, "time", "t", 0, "Time limit in seconds (0 for no limit)")
 	playCmd.Flags().BoolVarP(&hints, "hints", "i", true, "Enable hints")
+	playCmd.Flags().StringVarP(&botName, "bot", "b", "", "Let a built-in bot play instead of you (random, binary, hint)")
 }
 
 func runPlay(cmd *cobra.Command, args []string) error {
@@ -35,5 +39,13 @@ func runPlay(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to created game: %w", err)
 	}
 
+	if botName != "" {
+		p, err := bot.New(botName, time.Now().UnixNano())
+		if err != nil {
+			return err
+		}
+		g.SetPlayer(p)
+	}
+
 	return g.Play()
 }
\ No newline at end of file
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/samnart1/GoLang-Projects/004guessgame/internal/game"
+	"github.com/samnart1/GoLang-Projects/004guessgame/internal/storage"
+	"github.com/samnart1/GoLang-Projects/004guessgame/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var resumeCmd = &cobra.Command{
+	Use: "resume",
+	Short: "Resume a saved game",
+	Long: "Continue the game saved with the 'save' command, with its guesses and remaining time",
+	RunE: runResume,
+}
+
+func runResume(cmd *cobra.Command, args []string) error {
+	saved, err := storage.LoadSavedGame()
+	if err != nil {
+		return fmt.Errorf("failed to load saved game: %w", err)
+	}
+	if saved == nil {
+		return fmt.Errorf("no saved game found")
+	}
+
+	g, err := game.Restore(saved)
+	if err != nil {
+		return err
+	}
+
+	// the game only stays on disk if the player saves it again
+	if err := storage.DeleteSavedGame(); err != nil {
+		return err
+	}
+
+	ui.ShowWelcome()
+	return g.Play()
+}
\ No newline at end of file
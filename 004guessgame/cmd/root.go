@@ -16,5 +16,7 @@ func init() {
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(playCmd)
 	rootCmd.AddCommand(resetCmd)
+	rootCmd.AddCommand(resumeCmd)
 	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(tournamentCmd)
 }
\ No newline at end of file
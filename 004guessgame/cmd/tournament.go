@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/samnart1/GoLang-Projects/004guessgame/internal/bot"
+	"github.com/samnart1/GoLang-Projects/004guessgame/internal/game"
+	"github.com/samnart1/GoLang-Projects/004guessgame/internal/tournament"
+	"github.com/samnart1/GoLang-Projects/004guessgame/pkg/random"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tournamentGames			int
+	tournamentSeed			int64
+	tournamentDifficulty	string
+	tournamentHints			bool
+	tournamentMaxGuesses	int
+	tournamentBots			[]string
+	tournamentExternal		[]string
+)
+
+// botSeedMix separates the bots' random stream from the targets, which are
+// drawn from the tournament seed itself.
+const botSeedMix = 0x5bd1e9955bd1e995
+
+var tournamentCmd = &cobra.Command{
+	Use: "tournament",
+	Short: "Pit guessing bots against each other",
+	Long: `Play many seeded games with each bot and print a ranking table.
+
+External bots are programs that read JSON lines on stdin and answer every
+{"type":"guess"} message with a line such as {"guess": 42}. Pass them as
+"name=command args" with --external. A bot that sends an invalid reply
+loses that game and is restarted for the next one.`,
+	RunE: runTournament,
+}
+
+func init() {
+	tournamentCmd.Flags().IntVarP(&tournamentGames, "games", "n", 1000, "Number of games each bot plays")
+	tournamentCmd.Flags().Int64VarP(&tournamentSeed, "seed", "s", 0, "Seed for the target numbers (0 picks one from the clock)")
+	tournamentCmd.Flags().StringVarP(&tournamentDifficulty, "difficulty", "d", "medium", "Game difficulty (easy, medium, hard, custom)")
+	tournamentCmd.Flags().BoolVarP(&tournamentHints, "hints", "i", true, "Enable hints")
+	tournamentCmd.Flags().IntVarP(&tournamentMaxGuesses, "max-guesses", "m", 100, "Guesses allowed per game before it counts as lost")
+	tournamentCmd.Flags().StringSliceVarP(&tournamentBots, "bots", "b", bot.Names(), "Built-in bots to enter")
+	tournamentCmd.Flags().StringArrayVarP(&tournamentExternal, "external", "e", nil, "External bot as name=command (repeatable)")
+}
+
+func runTournament(cmd *cobra.Command, args []string) error {
+	if tournamentSeed == 0 {
+		tournamentSeed = time.Now().UnixNano()
+	}
+
+	// bots draw from their own stream so they can't replay the targets
+	seeds := random.NewSeeded(tournamentSeed ^ botSeedMix)
+
+	var players []game.Player
+	for _, name := range tournamentBots {
+		p, err := bot.New(name, seeds.Int63())
+		if err != nil {
+			return err
+		}
+		players = append(players, p)
+	}
+
+	for _, spec := range tournamentExternal {
+		p, err := bot.NewExternal(spec)
+		if err != nil {
+			return err
+		}
+		players = append(players, p)
+	}
+
+	for _, p := range players {
+		if c, ok := p.(io.Closer); ok {
+			defer c.Close()
+		}
+	}
+
+	if len(players) == 0 {
+		return fmt.Errorf("no bots entered")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cfg := tournament.Config{
+		Games: tournamentGames,
+		Seed: tournamentSeed,
+		Difficulty: tournamentDifficulty,
+		Hints: tournamentHints,
+		MaxGuesses: tournamentMaxGuesses,
+	}
+
+	fmt.Printf("Running %d %s games per bot (seed %d, hints %t)\n\n", cfg.Games, cfg.Difficulty, cfg.Seed, cfg.Hints)
+
+	standings, err := tournament.Run(ctx, cfg, players)
+	if err != nil {
+		return err
+	}
+
+	return tournament.WriteTable(os.Stdout, standings)
+}
\ No newline at end of file
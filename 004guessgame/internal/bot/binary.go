@@ -0,0 +1,30 @@
+package bot
+
+import "github.com/samnart1/GoLang-Projects/004guessgame/internal/game"
+
+// Binary always guesses the middle of the remaining range.
+type Binary struct {
+	span span
+}
+
+func NewBinary() *Binary {
+	return &Binary{}
+}
+
+func (b *Binary) Name() string {
+	return "binary"
+}
+
+func (b *Binary) Start(min, max int, hints bool) error {
+	b.span = span{lo: min, hi: max}
+	return nil
+}
+
+func (b *Binary) Guess() (int, error) {
+	return b.span.mid(), nil
+}
+
+func (b *Binary) Observe(fb game.Feedback) error {
+	b.span.narrow(fb)
+	return nil
+}
\ No newline at end of file
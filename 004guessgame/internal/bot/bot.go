@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samnart1/GoLang-Projects/004guessgame/internal/game"
+)
+
+type factory func(seed int64) game.Player
+
+var builtins = map[string]factory{
+	"random": func(seed int64) game.Player { return NewRandom(seed) },
+	"binary": func(seed int64) game.Player { return NewBinary() },
+	"hint":   func(seed int64) game.Player { return NewHintAware() },
+}
+
+// New returns the built-in bot with the given name. The seed is only used by
+// bots that make random choices.
+func New(name string, seed int64) (game.Player, error) {
+	f, ok := builtins[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown bot: %s (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	return f(seed), nil
+}
+
+// Names lists the built-in bots in alphabetical order.
+func Names() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// span tracks the range of numbers that are still consistent with the
+// feedback seen so far.
+type span struct {
+	lo	int
+	hi	int
+}
+
+func (s *span) narrow(fb game.Feedback) {
+	switch fb.Outcome {
+	case game.TooLow:
+		s.lo = max(s.lo, fb.Guess+1)
+	case game.TooHigh:
+		s.hi = min(s.hi, fb.Guess-1)
+	case game.Correct:
+		s.lo, s.hi = fb.Guess, fb.Guess
+	}
+}
+
+func (s *span) mid() int {
+	if s.hi < s.lo {
+		return s.lo
+	}
+	return s.lo + (s.hi-s.lo)/2
+}
\ No newline at end of file
@@ -0,0 +1,187 @@
+package bot
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/samnart1/GoLang-Projects/004guessgame/internal/game"
+)
+
+// Message is one line of the external bot protocol. The game sends
+// "start", "guess" and "feedback" messages on the bot's stdin, and the bot
+// answers every "guess" with a line such as {"guess": 42} on its stdout.
+type Message struct {
+	Type		string	`json:"type"`
+	Min			int		`json:"min,omitempty"`
+	Max			int		`json:"max,omitempty"`
+	Hints		bool	`json:"hints,omitempty"`
+	Guess		*int	`json:"guess,omitempty"`
+	Result		string	`json:"result,omitempty"`
+	Proximity	string	`json:"proximity,omitempty"`
+}
+
+type reply struct {
+	Guess	*int	`json:"guess"`
+	Error	string	`json:"error,omitempty"`
+}
+
+// External runs a bot as a child process that speaks JSON lines over
+// stdin/stdout. The process is started once and reused for every game, and
+// restarted at the next game if it breaks the protocol.
+type External struct {
+	name	string
+	args	[]string
+	cmd		*exec.Cmd
+	stdin	io.WriteCloser
+	enc		*json.Encoder
+	out		*bufio.Scanner
+}
+
+// NewExternal starts command (split on whitespace) as a bot. An optional
+// "name=" prefix sets the name shown in results.
+func NewExternal(spec string) (*External, error) {
+	name, command := "", spec
+	if i := strings.Index(spec, "="); i > 0 && !strings.ContainsAny(spec[:i], " /") {
+		name, command = spec[:i], spec[i+1:]
+	}
+
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty bot command: %q", spec)
+	}
+	if name == "" {
+		name = filepath.Base(args[0])
+	}
+
+	e := &External{name: name, args: args}
+	if err := e.launch(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *External) launch() error {
+	cmd := exec.Command(e.args[0], e.args[1:]...)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start bot %s: %w", e.name, err)
+	}
+
+	e.cmd = cmd
+	e.stdin = stdin
+	e.enc = json.NewEncoder(stdin)
+	e.out = bufio.NewScanner(stdout)
+	return nil
+}
+
+func (e *External) Name() string {
+	return e.name
+}
+
+func (e *External) Start(min, max int, hints bool) error {
+	if e.cmd == nil {
+		if err := e.launch(); err != nil {
+			return err
+		}
+	}
+	return e.send(Message{Type: "start", Min: min, Max: max, Hints: hints})
+}
+
+func (e *External) Guess() (int, error) {
+	if err := e.send(Message{Type: "guess"}); err != nil {
+		return 0, err
+	}
+
+	guess, err := e.readReply()
+	if err != nil {
+		// the bot may still write the rest of its answer, which would be
+		// read as the reply to the next guess
+		e.kill()
+		return 0, err
+	}
+	return guess, nil
+}
+
+func (e *External) readReply() (int, error) {
+	if !e.out.Scan() {
+		if err := e.out.Err(); err != nil {
+			return 0, err
+		}
+		return 0, io.EOF
+	}
+
+	var r reply
+	if err := json.Unmarshal(e.out.Bytes(), &r); err != nil {
+		return 0, fmt.Errorf("invalid reply %q: %w", e.out.Text(), err)
+	}
+	if r.Error != "" {
+		return 0, fmt.Errorf("bot error: %s", r.Error)
+	}
+	if r.Guess == nil {
+		return 0, fmt.Errorf("reply has no guess: %q", e.out.Text())
+	}
+
+	return *r.Guess, nil
+}
+
+func (e *External) Observe(fb game.Feedback) error {
+	return e.send(Message{
+		Type: 		"feedback",
+		Guess: 		&fb.Guess,
+		Result: 	fb.Outcome.String(),
+		Proximity: 	fb.Proximity.String(),
+	})
+}
+
+// Close ends the bot's input and waits for it to exit.
+func (e *External) Close() error {
+	if e.cmd == nil {
+		return nil
+	}
+
+	cmd := e.cmd
+	e.cmd = nil
+	if err := e.stdin.Close(); err != nil {
+		return err
+	}
+	return cmd.Wait()
+}
+
+// kill stops a bot that is out of step with the protocol. A new process is
+// started for the next game.
+func (e *External) kill() {
+	if e.cmd == nil {
+		return
+	}
+
+	e.cmd.Process.Kill()
+	e.cmd.Wait()
+	e.cmd = nil
+}
+
+func (e *External) send(msg Message) error {
+	if e.cmd == nil {
+		return fmt.Errorf("bot %s is not running", e.name)
+	}
+	if err := e.enc.Encode(msg); err != nil {
+		e.kill()
+		return fmt.Errorf("failed to write to bot %s: %w", e.name, err)
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,50 @@
+package bot
+
+import "github.com/samnart1/GoLang-Projects/004guessgame/internal/game"
+
+// HintAware is a binary search that also uses the proximity reported with
+// each hint to cut the range down further. Without hints it behaves exactly
+// like Binary.
+type HintAware struct {
+	span span
+}
+
+func NewHintAware() *HintAware {
+	return &HintAware{}
+}
+
+func (h *HintAware) Name() string {
+	return "hint"
+}
+
+func (h *HintAware) Start(min, max int, hints bool) error {
+	h.span = span{lo: min, hi: max}
+	return nil
+}
+
+func (h *HintAware) Guess() (int, error) {
+	return h.span.mid(), nil
+}
+
+func (h *HintAware) Observe(fb game.Feedback) error {
+	h.span.narrow(fb)
+	if fb.Proximity == game.ProximityUnknown {
+		return nil
+	}
+
+	near, far := fb.Proximity.Bounds()
+	switch fb.Outcome {
+	case game.TooLow:
+		h.span.lo = max(h.span.lo, fb.Guess+near)
+		if far > 0 {
+			h.span.hi = min(h.span.hi, fb.Guess+far)
+		}
+	case game.TooHigh:
+		h.span.hi = min(h.span.hi, fb.Guess-near)
+		if far > 0 {
+			h.span.lo = max(h.span.lo, fb.Guess-far)
+		}
+	}
+
+	return nil
+}
\ No newline at end of file
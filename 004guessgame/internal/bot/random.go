@@ -0,0 +1,38 @@
+package bot
+
+import (
+	"github.com/samnart1/GoLang-Projects/004guessgame/internal/game"
+	"github.com/samnart1/GoLang-Projects/004guessgame/pkg/random"
+)
+
+// Random guesses uniformly among the numbers that higher/lower feedback has
+// not ruled out yet.
+type Random struct {
+	rng		*random.Seeded
+	span	span
+}
+
+func NewRandom(seed int64) *Random {
+	return &Random{rng: random.NewSeeded(seed)}
+}
+
+func (r *Random) Name() string {
+	return "random"
+}
+
+func (r *Random) Start(min, max int, hints bool) error {
+	r.span = span{lo: min, hi: max}
+	return nil
+}
+
+func (r *Random) Guess() (int, error) {
+	if r.span.hi < r.span.lo {
+		return r.span.lo, nil
+	}
+	return r.rng.IntRange(r.span.lo, r.span.hi)
+}
+
+func (r *Random) Observe(fb game.Feedback) error {
+	r.span.narrow(fb)
+	return nil
+}
\ No newline at end of file
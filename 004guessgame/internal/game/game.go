@@ -2,7 +2,9 @@ package game
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/samnart1/GoLang-Projects/004guessgame/internal/storage"
@@ -22,6 +24,16 @@ type Game struct {
 	guesses		int
 	startTime	time.Time
 	won			bool
+	player		Player
+	history		[]int
+}
+
+// Result summarises a finished game.
+type Result struct {
+	Target		int
+	Won			bool
+	Guesses		int
+	Duration	time.Duration
 }
 
 func New(difficultyStr string, timeLimit int, hints bool) (*Game, error) {
@@ -36,6 +48,28 @@ func New(difficultyStr string, timeLimit int, hints bool) (*Game, error) {
 		return nil, fmt.Errorf("failed to generate random number: %w", err)
 	}
 
+	return newGame(difficulty, target, timeLimit, hints), nil
+}
+
+// NewWithTarget creates a game with a fixed target, which is what seeded
+// tournaments use to give every player the same numbers.
+func NewWithTarget(difficultyStr string, target int, hints bool) (*Game, error) {
+	difficulty, err := ParseDifficulty(difficultyStr)
+	if err != nil {
+		return nil, err
+	}
+
+	min, max := difficulty.Range()
+	if err := validateGuess(target, min, max); err != nil {
+		return nil, fmt.Errorf("invalid target: %w", err)
+	}
+
+	return newGame(difficulty, target, 0, hints), nil
+}
+
+func newGame(difficulty Difficulty, target, timeLimit int, hints bool) *Game {
+	min, max := difficulty.Range()
+
 	var timeLimitDuration time.Duration
 	if timeLimit > 0 {
 		timeLimitDuration = time.Duration(timeLimit) * time.Second
@@ -49,92 +83,190 @@ func New(difficultyStr string, timeLimit int, hints bool) (*Game, error) {
 		timeLimit: 	timeLimitDuration,
 		hints: 		hints,
 		startTime: 	time.Now(),
-	}, nil
+		player: 	NewHumanPlayer(),
+	}
 }
 
-func (g *Game) Play() error {
-	ctx := context.Background()
-	var cancel context.CancelFunc
-
-	if g.timeLimit > 0 {
-		ctx, cancel = context.WithTimeout(ctx, g.timeLimit)
-		defer cancel()
+// SetPlayer replaces the default human player.
+func (g *Game) SetPlayer(p Player) {
+	g.player = p
+}
 
+func (g *Game) Play() error {
+	if g.timer == nil {
 		g.timer = timer.New(g.timeLimit)
-		go g.timer.Start()
-		defer func() {
-			if g.timer != nil {
-				g.timer.Stop()
-			}
-		}()
 	}
+	go g.timer.Start()
+	defer g.timer.Stop()
 
 	ui.ShowGameStart(g.min, g.max, g.difficulty.String(), g.timeLimit)
 
+	if err := g.player.Start(g.min, g.max, g.hints); err != nil {
+		return fmt.Errorf("failed to start player %s: %w", g.player.Name(), err)
+	}
+
+	if err := g.replay(); err != nil {
+		return err
+	}
+
 	for {
-		select {
-		case <-ctx.Done():
+		if g.expired() {
 			ui.ShowTimeout()
 			ui.ShowAnswer(g.target)
 			return g.endGame()
-		default:
-			guess, err := ui.GetGuess(g.min, g.max)
-			if err != nil {
-				ui.ShowError(err)
-				continue
-			}
-			
-			g.guesses++
+		}
 
-			if guess == g.target {
-				g.won = true
-				ui.ShowWin(g.guesses, time.Since(g.startTime))
-				return g.endGame()
-			}
+		if g.timer.Limited() {
+			ui.ShowCountdown(timer.Format(g.timer.Remaining()))
+		}
 
-			if g.hints {
-				hint := g.generateHint(guess)
-				ui.ShowHint(hint)
-			} else {
-				// still showing basic higher/lower without detailed hints
-				if guess < g.target {
-					ui.ShowHint("Too low!")
-				} else {
-					ui.ShowHint("Too high!")
-				}
+		guess, err := g.nextGuess()
+		switch {
+		case errors.Is(err, errTimeUp):
+			// the clock ran out while waiting, which the loop reports
+			continue
+		case errors.Is(err, ui.ErrPause):
+			if err := g.pause(); err != nil {
+				return err
 			}
+			continue
+		case errors.Is(err, ui.ErrSave):
+			return g.save()
+		case errors.Is(err, io.EOF):
+			return err
+		case err != nil:
+			ui.ShowError(err)
+			continue
+		}
+
+		// a guess typed after the clock ran out doesn't count
+		if g.expired() {
+			continue
+		}
+
+		fb, err := g.turn(guess)
+		if err != nil {
+			ui.ShowError(err)
+			continue
+		}
+
+		if fb.Outcome == Correct {
+			ui.ShowWin(g.guesses, g.timer.Elapsed())
+			return g.endGame()
 		}
+
+		ui.ShowHint(fb.Hint)
+	}
+}
+
+// errTimeUp is returned by nextGuess when the clock runs out first.
+var errTimeUp = errors.New("time is up")
+
+// nextGuess waits for the player's next guess, giving up when the time limit
+// runs out so a player who stops typing still loses. The read left behind
+// is abandoned along with the game.
+func (g *Game) nextGuess() (int, error) {
+	type answer struct {
+		guess	int
+		err		error
+	}
+
+	answers := make(chan answer, 1)
+	go func() {
+		guess, err := g.player.Guess()
+		answers <- answer{guess, err}
+	}()
+
+	select {
+	case a := <-answers:
+		return a.guess, a.err
+	case <-g.timer.Expired():
+		return 0, errTimeUp
 	}
 }
 
-func (g *Game) generateHint(guess int) string {
-	diff := abs(g.target - guess)
-
-	if guess < g.target {
-		if diff <= 2 {
-			return "Very close! Go higher."
-		} else if diff <= 5 {
-			return "Close! Go higher."
-		} else if diff <= 10 {
-			return "Go higher!!"
-		} else {
-			return "much higher"
+func (g *Game) expired() bool {
+	return g.timer.Limited() && g.timer.Remaining() <= 0
+}
+
+func (g *Game) pause() error {
+	g.timer.Pause()
+	ui.ShowPaused()
+
+	if err := ui.WaitForEnter(); err != nil {
+		return err
+	}
+
+	g.timer.Resume()
+
+	var remaining string
+	if g.timer.Limited() {
+		remaining = timer.Format(g.timer.Remaining())
+	}
+	ui.ShowResumed(remaining)
+	return nil
+}
+
+// Run plays the game without any terminal output or saved statistics. It stops
+// when the target is found, maxGuesses is reached (0 means no limit) or ctx is
+// done.
+func (g *Game) Run(ctx context.Context, maxGuesses int) (*Result, error) {
+	g.startTime = time.Now()
+
+	if err := g.player.Start(g.min, g.max, g.hints); err != nil {
+		return g.result(), fmt.Errorf("failed to start player %s: %w", g.player.Name(), err)
+	}
+
+	for !g.won && (maxGuesses <= 0 || g.guesses < maxGuesses) {
+		if err := ctx.Err(); err != nil {
+			return g.result(), err
 		}
-	} else {
-		if diff <= 2 {
-			return "Very close! Go lower."
-		} else if diff <= 5 {
-			return "Close! Go lower."
-		} else if diff <= 10 {
-			return "Goo lower!"
-		} else {
-			return "Much lower"
+
+		guess, err := g.player.Guess()
+		if err != nil {
+			return g.result(), fmt.Errorf("player %s failed to guess: %w", g.player.Name(), err)
+		}
+
+		if _, err := g.turn(guess); err != nil {
+			return g.result(), fmt.Errorf("player %s: %w", g.player.Name(), err)
 		}
 	}
+
+	return g.result(), nil
+}
+
+// turn scores a single guess and reports the feedback back to the player.
+func (g *Game) turn(guess int) (Feedback, error) {
+	if err := validateGuess(guess, g.min, g.max); err != nil {
+		return Feedback{}, err
+	}
+
+	g.guesses++
+	g.history = append(g.history, guess)
+
+	fb := g.feedback(guess)
+	if fb.Outcome == Correct {
+		g.won = true
+	}
+
+	if err := g.player.Observe(fb); err != nil {
+		return fb, err
+	}
+
+	return fb, nil
+}
+
+func (g *Game) result() *Result {
+	return &Result{
+		Target: 	g.target,
+		Won: 		g.won,
+		Guesses: 	g.guesses,
+		Duration: 	time.Since(g.startTime),
+	}
 }
 
 func (g *Game) endGame() error {
-	duration := time.Since(g.startTime)
+	duration := g.timer.Elapsed()
 
 	stats, err := storage.LoadStats()
 	if err != nil {
@@ -1 +1,151 @@
-package game
\ No newline at end of file
+package game
+
+import "fmt"
+
+// Outcome is the result of comparing a guess against the target.
+type Outcome int
+
+const (
+	Correct Outcome = iota
+	TooLow
+	TooHigh
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Correct:
+		return "correct"
+	case TooLow:
+		return "low"
+	case TooHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+// Proximity buckets the distance between a guess and the target. It is only
+// reported when hints are enabled.
+type Proximity int
+
+const (
+	ProximityUnknown Proximity = iota
+	VeryClose
+	Close
+	Near
+	Far
+)
+
+func (p Proximity) String() string {
+	switch p {
+	case VeryClose:
+		return "very_close"
+	case Close:
+		return "close"
+	case Near:
+		return "near"
+	case Far:
+		return "far"
+	default:
+		return ""
+	}
+}
+
+// Bounds returns the smallest and largest distance a proximity can stand for.
+// Far has no upper bound, so max is -1.
+func (p Proximity) Bounds() (int, int) {
+	switch p {
+	case VeryClose:
+		return 1, 2
+	case Close:
+		return 3, 5
+	case Near:
+		return 6, 10
+	case Far:
+		return 11, -1
+	default:
+		return 1, -1
+	}
+}
+
+// Feedback is what a player learns after each guess.
+type Feedback struct {
+	Guess		int
+	Outcome		Outcome
+	Proximity	Proximity
+	Hint		string
+}
+
+func proximityOf(diff int) Proximity {
+	switch {
+	case diff <= 2:
+		return VeryClose
+	case diff <= 5:
+		return Close
+	case diff <= 10:
+		return Near
+	default:
+		return Far
+	}
+}
+
+func (g *Game) feedback(guess int) Feedback {
+	fb := Feedback{Guess: guess}
+
+	switch {
+	case guess == g.target:
+		fb.Outcome = Correct
+		return fb
+	case guess < g.target:
+		fb.Outcome = TooLow
+	default:
+		fb.Outcome = TooHigh
+	}
+
+	if g.hints {
+		fb.Proximity = proximityOf(abs(g.target - guess))
+		fb.Hint = generateHint(fb.Outcome, fb.Proximity)
+	} else {
+		// still showing basic higher/lower without detailed hints
+		if fb.Outcome == TooLow {
+			fb.Hint = "Too low!"
+		} else {
+			fb.Hint = "Too high!"
+		}
+	}
+
+	return fb
+}
+
+func generateHint(outcome Outcome, proximity Proximity) string {
+	if outcome == TooLow {
+		switch proximity {
+		case VeryClose:
+			return "Very close! Go higher."
+		case Close:
+			return "Close! Go higher."
+		case Near:
+			return "Go higher!!"
+		default:
+			return "much higher"
+		}
+	}
+
+	switch proximity {
+	case VeryClose:
+		return "Very close! Go lower."
+	case Close:
+		return "Close! Go lower."
+	case Near:
+		return "Goo lower!"
+	default:
+		return "Much lower"
+	}
+}
+
+func (f Feedback) String() string {
+	if f.Proximity == ProximityUnknown {
+		return fmt.Sprintf("%d: %s", f.Guess, f.Outcome)
+	}
+	return fmt.Sprintf("%d: %s (%s)", f.Guess, f.Outcome, f.Proximity)
+}
\ No newline at end of file
@@ -0,0 +1,40 @@
+package game
+
+import "github.com/samnart1/GoLang-Projects/004guessgame/internal/ui"
+
+// Player is anything that can make guesses in a Game. Game drives the player:
+// Start is called once per game, then Guess and Observe alternate until the
+// target is found, the time runs out or the guess budget is spent.
+type Player interface {
+	Name() string
+	Start(min, max int, hints bool) error
+	Guess() (int, error)
+	Observe(fb Feedback) error
+}
+
+// HumanPlayer reads guesses from the terminal.
+type HumanPlayer struct {
+	min	int
+	max	int
+}
+
+func NewHumanPlayer() *HumanPlayer {
+	return &HumanPlayer{}
+}
+
+func (h *HumanPlayer) Name() string {
+	return "human"
+}
+
+func (h *HumanPlayer) Start(min, max int, hints bool) error {
+	h.min, h.max = min, max
+	return nil
+}
+
+func (h *HumanPlayer) Guess() (int, error) {
+	return ui.GetGuess(h.min, h.max)
+}
+
+func (h *HumanPlayer) Observe(fb Feedback) error {
+	return nil
+}
\ No newline at end of file
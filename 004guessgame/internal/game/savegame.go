@@ -0,0 +1,86 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samnart1/GoLang-Projects/004guessgame/internal/storage"
+	"github.com/samnart1/GoLang-Projects/004guessgame/internal/timer"
+	"github.com/samnart1/GoLang-Projects/004guessgame/internal/ui"
+)
+
+// Restore rebuilds an unfinished game from disk. The clock continues from the
+// time already used and earlier guesses are replayed to the player when the
+// game is played.
+func Restore(saved *storage.SavedGame) (*Game, error) {
+	difficulty, err := ParseDifficulty(saved.Difficulty)
+	if err != nil {
+		return nil, err
+	}
+
+	min, max := difficulty.Range()
+	if err := validateGuess(saved.Target, min, max); err != nil {
+		return nil, fmt.Errorf("invalid saved game: %w", err)
+	}
+
+	g := newGame(difficulty, saved.Target, 0, saved.Hints)
+	g.timeLimit = saved.TimeLimit
+	g.timer = timer.Restore(saved.TimeLimit, saved.Elapsed)
+
+	for _, guess := range saved.Guesses {
+		if err := validateGuess(guess, min, max); err != nil {
+			return nil, fmt.Errorf("invalid saved game: %w", err)
+		}
+	}
+	g.history = saved.Guesses
+	g.guesses = len(saved.Guesses)
+
+	return g, nil
+}
+
+// replay shows earlier guesses of a restored game and feeds them to the
+// player so it starts from the same knowledge.
+func (g *Game) replay() error {
+	if len(g.history) == 0 {
+		return nil
+	}
+
+	hints := make([]string, len(g.history))
+	for i, guess := range g.history {
+		fb := g.feedback(guess)
+		if err := g.player.Observe(fb); err != nil {
+			return err
+		}
+		hints[i] = fb.Hint
+	}
+
+	ui.ShowHistory(g.history, hints)
+	return nil
+}
+
+// save writes the game to disk so it can be resumed later.
+func (g *Game) save() error {
+	g.timer.Pause()
+
+	saved := &storage.SavedGame{
+		Difficulty: g.difficulty.String(),
+		Target: 	g.target,
+		Hints: 		g.hints,
+		TimeLimit: 	g.timeLimit,
+		Elapsed: 	g.timer.Elapsed(),
+		Guesses: 	g.history,
+		SavedAt: 	time.Now(),
+	}
+
+	if err := storage.SaveGame(saved); err != nil {
+		return fmt.Errorf("failed to save game: %w", err)
+	}
+
+	path, err := storage.SavedGamePath()
+	if err != nil {
+		return err
+	}
+	ui.ShowSaved(path)
+
+	return nil
+}
\ No newline at end of file
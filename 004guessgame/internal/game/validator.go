@@ -1 +1,10 @@
-package game
\ No newline at end of file
+package game
+
+import "fmt"
+
+func validateGuess(guess, min, max int) error {
+	if guess < min || guess > max {
+		return fmt.Errorf("guess %d is outside the range %d-%d", guess, min, max)
+	}
+	return nil
+}
\ No newline at end of file
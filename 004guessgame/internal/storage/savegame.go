@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SavedGame is an unfinished game stored so it can be resumed later.
+type SavedGame struct {
+	Difficulty	string			`json:"difficulty"`
+	Target		int				`json:"target"`
+	Hints		bool			`json:"hints"`
+	TimeLimit	time.Duration	`json:"time_limit"`
+	Elapsed		time.Duration	`json:"elapsed"`
+	Guesses		[]int			`json:"guesses"`
+	SavedAt		time.Time		`json:"saved_at"`
+}
+
+// LoadSavedGame returns the saved game, or nil if there isn't one.
+func LoadSavedGame() (*SavedGame, error) {
+	path, err := SavedGamePath()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var saved SavedGame
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+
+func SaveGame(saved *SavedGame) error {
+	path, err := SavedGamePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(saved, "", " ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+
+func DeleteSavedGame() error {
+	path, err := SavedGamePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+
+func SavedGamePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".config", "guess-game", "saved_game.json"), nil
+}
\ No newline at end of file
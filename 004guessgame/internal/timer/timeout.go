@@ -1 +1,55 @@
-package timer
\ No newline at end of file
+package timer
+
+import (
+	"fmt"
+	"time"
+)
+
+// checkpoints are the remaining times at which the countdown is announced.
+var checkpoints = []time.Duration{
+	60 * time.Second,
+	30 * time.Second,
+	20 * time.Second,
+	10 * time.Second,
+	5 * time.Second,
+	3 * time.Second,
+	2 * time.Second,
+	1 * time.Second,
+}
+
+// announcer prints each checkpoint at most once, so pausing and resuming
+// doesn't repeat warnings that were already shown.
+type announcer struct {
+	next int
+}
+
+func newAnnouncer(remaining time.Duration) *announcer {
+	a := &announcer{}
+	for a.next < len(checkpoints) && checkpoints[a.next] >= remaining {
+		a.next++
+	}
+	return a
+}
+
+func (a *announcer) announce(remaining time.Duration) {
+	var hit bool
+	for a.next < len(checkpoints) && remaining <= checkpoints[a.next] {
+		a.next++
+		hit = true
+	}
+	if !hit {
+		return
+	}
+
+	if remaining > 10*time.Second {
+		fmt.Printf("\n%v remaining!\n", remaining.Round(time.Second))
+	} else {
+		fmt.Printf("\nOnly %v left!\n", remaining.Round(time.Second))
+	}
+}
+
+// Format renders a duration as a m:ss countdown.
+func Format(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
\ No newline at end of file
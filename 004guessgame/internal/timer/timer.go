@@ -1,48 +1,132 @@
 package timer
 
 import (
-	"fmt"
+	"sync"
 	"time"
 )
 
+// Timer is a pausable game clock. With a zero duration it only measures
+// elapsed time and never expires.
 type Timer struct {
+	mu			sync.Mutex
 	duration 	time.Duration
+	elapsed		time.Duration
+	started		time.Time
+	paused		bool
+	expired		chan struct{}
 	done 		chan bool
 }
 
 func New(duration time.Duration) *Timer {
+	return Restore(duration, 0)
+}
+
+// Restore creates a paused-in-time clock that has already used elapsed, for
+// games resumed from disk.
+func Restore(duration, elapsed time.Duration) *Timer {
 	return &Timer{
 		duration: 	duration,
+		elapsed: 	elapsed,
+		started: 	time.Now(),
+		expired: 	make(chan struct{}),
 		done: 		make(chan bool),
 	}
 }
 
 func (t *Timer) Start() {
-	ticker := time.NewTicker(10 * time.Second)
+	if t.duration <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(250 * time.Millisecond)
 	defer ticker.Stop()
 
-	deadline := time.Now().Add(t.duration)
+	announcer := newAnnouncer(t.Remaining())
 
 	for {
 		select {
 		case <- t.done:
 			return
 		case <- ticker.C:
-			remaining := time.Until(deadline)
+			if t.Paused() {
+				continue
+			}
+
+			remaining := t.Remaining()
 			if remaining <= 0 {
+				close(t.expired)
 				return
 			}
 
-			// show countdown for last 30 seconds
-			if remaining <= 30*time.Second && remaining > 20*time.Second {
-				fmt.Printf("%v remaining!\n", remaining.Round(time.Second))
-			} else if remaining <= 10*time.Second {
-				fmt.Printf("Only %v left!\n", remaining.Round(time.Second))
-			}
+			announcer.announce(remaining)
 		}
 	}
 }
 
+// Pause stops the clock; paused time is not counted as elapsed.
+func (t *Timer) Pause() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.paused {
+		return
+	}
+	t.elapsed += time.Since(t.started)
+	t.paused = true
+}
+
+func (t *Timer) Resume() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.paused {
+		return
+	}
+	t.started = time.Now()
+	t.paused = false
+}
+
+func (t *Timer) Paused() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.paused
+}
+
+// Elapsed is the time the clock has been running, excluding pauses.
+func (t *Timer) Elapsed() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.paused {
+		return t.elapsed
+	}
+	return t.elapsed + time.Since(t.started)
+}
+
+// Remaining is the time left before the timer expires, or zero for timers
+// without a limit.
+func (t *Timer) Remaining() time.Duration {
+	if t.duration <= 0 {
+		return 0
+	}
+
+	remaining := t.duration - t.Elapsed()
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+func (t *Timer) Limited() bool {
+	return t.duration > 0
+}
+
+// Expired is closed once the time limit has been used up.
+func (t *Timer) Expired() <-chan struct{} {
+	return t.expired
+}
+
 func (t *Timer) Stop() {
 	if t.done != nil {
 		select {
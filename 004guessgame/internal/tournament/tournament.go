@@ -0,0 +1,155 @@
+package tournament
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/samnart1/GoLang-Projects/004guessgame/internal/game"
+	"github.com/samnart1/GoLang-Projects/004guessgame/pkg/random"
+)
+
+type Config struct {
+	Games		int
+	Seed		int64
+	Difficulty	string
+	Hints		bool
+	MaxGuesses	int
+}
+
+// Standing is one player's aggregated results.
+type Standing struct {
+	Name			string
+	Games			int
+	Wins			int
+	Errors			int
+	TotalGuesses	int
+	BestGuesses		int
+	WorstGuesses	int
+	TotalTime		time.Duration
+}
+
+func (s *Standing) WinRate() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	return float64(s.Wins) / float64(s.Games) * 100
+}
+
+// AvgGuesses is averaged over won games only.
+func (s *Standing) AvgGuesses() float64 {
+	if s.Wins == 0 {
+		return 0
+	}
+	return float64(s.TotalGuesses) / float64(s.Wins)
+}
+
+func (s *Standing) record(res *game.Result, err error) {
+	s.Games++
+	s.TotalTime += res.Duration
+
+	if err != nil {
+		s.Errors++
+		return
+	}
+	if !res.Won {
+		return
+	}
+
+	s.Wins++
+	s.TotalGuesses += res.Guesses
+	if s.BestGuesses == 0 || res.Guesses < s.BestGuesses {
+		s.BestGuesses = res.Guesses
+	}
+	if res.Guesses > s.WorstGuesses {
+		s.WorstGuesses = res.Guesses
+	}
+}
+
+// Run plays cfg.Games games for every player. Targets are drawn from cfg.Seed,
+// so every player faces the same sequence of numbers and runs are
+// reproducible. The returned standings are ranked best first.
+func Run(ctx context.Context, cfg Config, players []game.Player) ([]*Standing, error) {
+	if cfg.Games <= 0 {
+		return nil, fmt.Errorf("number of games must be positive, got %d", cfg.Games)
+	}
+
+	difficulty, err := game.ParseDifficulty(cfg.Difficulty)
+	if err != nil {
+		return nil, err
+	}
+
+	min, max := difficulty.Range()
+	rng := random.NewSeeded(cfg.Seed)
+	targets := make([]int, cfg.Games)
+	for i := range targets {
+		if targets[i], err = rng.IntRange(min, max); err != nil {
+			return nil, err
+		}
+	}
+
+	standings := make([]*Standing, 0, len(players))
+	for _, p := range players {
+		st := &Standing{Name: p.Name()}
+
+		for _, target := range targets {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			g, err := game.NewWithTarget(cfg.Difficulty, target, cfg.Hints)
+			if err != nil {
+				return nil, err
+			}
+			g.SetPlayer(p)
+
+			st.record(g.Run(ctx, cfg.MaxGuesses))
+		}
+
+		standings = append(standings, st)
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		if a.AvgGuesses() != b.AvgGuesses() {
+			return a.AvgGuesses() < b.AvgGuesses()
+		}
+		return a.TotalTime < b.TotalTime
+	})
+
+	return standings, nil
+}
+
+// WriteTable prints the standings as an aligned ranking table.
+func WriteTable(w io.Writer, standings []*Standing) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(tw, "Rank\tBot\tGames\tWins\tWin %\tAvg Guesses\tBest\tWorst\tErrors\tAvg Time\t")
+	for i, s := range standings {
+		var avgTime time.Duration
+		if s.Games > 0 {
+			avgTime = s.TotalTime / time.Duration(s.Games)
+		}
+
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%.1f\t%.2f\t%d\t%d\t%d\t%v\t\n",
+			i+1,
+			s.Name,
+			s.Games,
+			s.Wins,
+			s.WinRate(),
+			s.AvgGuesses(),
+			s.BestGuesses,
+			s.WorstGuesses,
+			s.Errors,
+			avgTime.Round(time.Microsecond),
+		)
+	}
+
+	return tw.Flush()
+}
\ No newline at end of file
@@ -21,9 +21,40 @@ func ShowGameStart(min, max int, difficulty string, timeLimit time.Duration) {
 	}
 	fmt.Printf("I'm thinking of a number between %d and %d\n", min, max)
 	fmt.Println("Let's see how many guesses it takes you!")
+	fmt.Println("Type 'pause' to stop the clock or 'save' to finish later.")
 	fmt.Println()
 }
 
+func ShowCountdown(remaining string) {
+	fmt.Printf("[%s left] ", remaining)
+}
+
+func ShowPaused() {
+	fmt.Println("Game paused. The clock is stopped.")
+	fmt.Print("Press Enter to resume...")
+}
+
+func ShowResumed(remaining string) {
+	fmt.Println("Resumed!")
+	if remaining != "" {
+		fmt.Printf("Time remaining: %s\n", remaining)
+	}
+	fmt.Println()
+}
+
+func ShowHistory(guesses []int, hints []string) {
+	fmt.Println("Your guesses so far:")
+	for i, guess := range guesses {
+		fmt.Printf("  %d: %s\n", guess, hints[i])
+	}
+	fmt.Println()
+}
+
+func ShowSaved(path string) {
+	fmt.Printf("Game saved to %s\n", path)
+	fmt.Println("Run 'guess-game resume' to continue it later.")
+}
+
 func ShowHint(hint string) {
 	fmt.Printf("%s\n", hint)
 	fmt.Println()
@@ -2,18 +2,27 @@ package ui
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
 )
 
-func GetGuess(min, max int) (int, error) {
-	reader := bufio.NewReader(os.Stdin)
+var (
+	// ErrPause is returned by GetGuess when the player asks to pause.
+	ErrPause = errors.New("game paused")
+	// ErrSave is returned by GetGuess when the player asks to save and quit.
+	ErrSave = errors.New("game saved")
+)
 
+// stdin is shared so buffered input isn't lost between prompts.
+var stdin = bufio.NewReader(os.Stdin)
+
+func GetGuess(min, max int) (int, error) {
 	for {
 		fmt.Printf("Enter your guess (%d-%d): ", min, max)
-		input, err := reader.ReadString('\n')
+		input, err := stdin.ReadString('\n')
 		if err != nil {
 			return 0, err
 		}
@@ -25,6 +34,13 @@ func GetGuess(min, max int) (int, error) {
 			os.Exit(0)
 		}
 
+		switch trimmed {
+		case "pause", "p":
+			return 0, ErrPause
+		case "save", "s":
+			return 0, ErrSave
+		}
+
 		guess, err := strconv.Atoi(strings.TrimSpace(string(input)))
 		if err != nil {
 			fmt.Println("Please enter a valid guess number")
@@ -39,4 +55,10 @@ func GetGuess(min, max int) (int, error) {
 		return guess, nil
 
 	}
+}
+
+// WaitForEnter blocks until the player presses Enter.
+func WaitForEnter() error {
+	_, err := stdin.ReadString('\n')
+	return err
 }
\ No newline at end of file
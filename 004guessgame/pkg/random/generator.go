@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"fmt"
 	"math/big"
+	mrand "math/rand"
 )
 
 func IntRange(min, max int) (int, error) {
@@ -22,4 +23,26 @@ func IntRange(min, max int) (int, error) {
 	}
 
 	return int(n.Int64()) + min, nil
+}
+
+// Seeded is a deterministic generator for simulations that must be
+// reproducible. It is not safe for concurrent use.
+type Seeded struct {
+	rng *mrand.Rand
+}
+
+func NewSeeded(seed int64) *Seeded {
+	return &Seeded{rng: mrand.New(mrand.NewSource(seed))}
+}
+
+func (s *Seeded) IntRange(min, max int) (int, error) {
+	if min > max {
+		return 0, fmt.Errorf("min (%d) cannot be greater than max (%d)", min, max)
+	}
+
+	return s.rng.Intn(max-min+1) + min, nil
+}
+
+func (s *Seeded) Int63() int64 {
+	return s.rng.Int63()
 }
\ No newline at end of file
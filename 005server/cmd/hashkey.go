@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/samnart1/GoLang-Projects/005server/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+var hashKeyCmd = &cobra.Command{
+	Use: "hash-key <name> <key>",
+	Short: "Hash an API key for AUTH_API_KEYS",
+	Long: `Print the name:sha256 entry to add to AUTH_API_KEYS for an API key`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Printf("%s:%s\n", args[0], auth.HashKey(args[1]))
+	},
+}
\ No newline at end of file
@@ -23,4 +23,5 @@ func Execute() {
 func init() {
 	rootCmd.AddCommand(serverCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(hashKeyCmd)
 }
\ No newline at end of file
@@ -6,7 +6,6 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/samnart1/GoLang-Projects/005server/internal/config"
 	"github.com/samnart1/GoLang-Projects/005server/internal/logger"
@@ -34,28 +33,48 @@ func init() {
 }
 
 func runServer(cmd *cobra.Command, args []string) {
-	cfg, err := config.Load()
+	// flags keep overriding the environment across reloads
+	load := func() (*config.Config, error) {
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, err
+		}
+
+		if cmd.Flags().Changed("port") {
+			cfg.Port = port
+		}
+		if cmd.Flags().Changed("env") {
+			cfg.Environment = environment
+		}
+		if cmd.Flags().Changed("log-level") {
+			cfg.LogLevel = logLevel
+		}
+		return cfg, nil
+	}
+
+	cfg, err := load()
 	if err != nil {
 		fmt.Printf("Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
 
-	if cmd.Flags().Changed("port") {
-		cfg.Port = port
-	}
-	if cmd.Flags().Changed("env") {
-		cfg.Environment = environment
-	}
-	if cmd.Flags().Changed("log-level") {
-		cfg.LogLevel = logLevel
-	}
-
 	log := logger.New(cfg.LogLevel, cfg.Environment)
+	Serve(cfg, log, load)
+}
 
-	srv := server.New(cfg, log)
+// Serve runs the server until it is told to stop. SIGHUP reloads the
+// configuration through load, SIGUSR2 hands the listening sockets to a new
+// process and SIGINT or SIGTERM shut down. In-flight requests get
+// ShutdownTimeout to finish.
+func Serve(cfg *config.Config, log *logger.Logger, load func() (*config.Config, error)) {
+	srv, err := server.New(cfg, log)
+	if err != nil {
+		log.Error("Failed to create server", "error", err)
+		os.Exit(1)
+	}
 
 	go func() {
-		log.Info("Starting http server", "port", cfg.Port, "env", cfg.Environment)
+		log.Info("Starting http server", "port", cfg.Port, "env", cfg.Environment, "pid", os.Getpid())
 		if err := srv.Start(); err != nil {
 			log.Error("Server failed to start", "error", err)
 			os.Exit(1)
@@ -63,13 +82,39 @@ func runServer(cmd *cobra.Command, args []string) {
 	}()
 
 	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGUSR2)
+
+wait:
+	for sig := range quit {
+		switch sig {
+		case syscall.SIGHUP:
+			log.Info("Reloading configuration")
+			next, err := load()
+			if err != nil {
+				log.Error("Configuration reload failed, keeping the current one", "error", err)
+				continue
+			}
+			if err := srv.Reload(next); err != nil {
+				log.Error("Configuration reload failed", "error", err)
+				continue
+			}
+			cfg = next
+		case syscall.SIGUSR2:
+			log.Info("Handing over to a new process")
+			if err := srv.Upgrade(); err != nil {
+				log.Error("Upgrade failed, still serving", "error", err)
+				continue
+			}
+			break wait
+		default:
+			break wait
+		}
+	}
 
-	log.Info("Shutting down server...")
+	log.Info("Shutting down server...", "timeout", cfg.ShutdownTimeout)
 
 	//shutdown gracefully
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/samnart1/GoLang-Projects/005server/internal/config"
+)
+
+type apiKey struct {
+	name	string
+	hash	[]byte
+}
+
+// APIKeys accepts static keys sent in a header. Only their SHA-256 hashes
+// are kept.
+type APIKeys struct {
+	header	string
+	keys	[]apiKey
+}
+
+func NewAPIKeys(header string, keys []config.APIKey) *APIKeys {
+	k := &APIKeys{header: header}
+	for _, key := range keys {
+		// config has already checked the hash is valid hex
+		hash, _ := hex.DecodeString(key.Hash)
+		k.keys = append(k.keys, apiKey{name: key.Name, hash: hash})
+	}
+	return k
+}
+
+// HashKey returns the value to put in AUTH_API_KEYS for key.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (k *APIKeys) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get(k.header)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+
+	sum := sha256.Sum256([]byte(key))
+
+	// compare against every key so the time taken does not depend on which
+	// one matched
+	var name string
+	for _, entry := range k.keys {
+		if subtle.ConstantTimeCompare(sum[:], entry.hash) == 1 {
+			name = entry.name
+		}
+	}
+
+	if name == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &Principal{
+		Subject: name,
+		Method: "api_key",
+		Claims: map[string]interface{}{"sub": name},
+	}, nil
+}
+
+func (k *APIKeys) Challenge(realm string) string {
+	return ""
+}
\ No newline at end of file
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrNoCredentials means the request carried nothing for this method, so
+	// the next one may be tried.
+	ErrNoCredentials = errors.New("authentication required")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrForbidden means the caller is known but lacks a required claim.
+	ErrForbidden = errors.New("insufficient permissions")
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject	string					`json:"subject"`
+	Method	string					`json:"method"`
+	Claims	map[string]interface{}	`json:"claims,omitempty"`
+}
+
+// HasClaim reports whether the claim name holds want. Space separated
+// strings such as scope and lists match when any element equals want.
+func (p *Principal) HasClaim(name, want string) bool {
+	switch v := p.Claims[name].(type) {
+	case nil:
+		return false
+	case string:
+		for _, field := range strings.Fields(v) {
+			if field == want {
+				return true
+			}
+		}
+		return v == want
+	case []interface{}:
+		for _, item := range v {
+			if fmt.Sprint(item) == want {
+				return true
+			}
+		}
+		return false
+	default:
+		return fmt.Sprint(v) == want
+	}
+}
+
+// Authenticator checks one kind of credentials on a request.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+	// Challenge is the WWW-Authenticate value sent when authentication
+	// fails, or empty if the method has none.
+	Challenge(realm string) string
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the principal of the request, or nil on public routes.
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(contextKey{}).(*Principal)
+	return p
+}
\ No newline at end of file
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/samnart1/GoLang-Projects/005server/internal/config"
+)
+
+// Route is the authentication required under one path prefix.
+type Route struct {
+	prefix	string
+	methods	[]Authenticator
+	claims	map[string]string
+	realm	string
+}
+
+// Guard maps request paths to the routes protecting them. Paths that match
+// no route are public.
+type Guard struct {
+	routes	[]*Route
+}
+
+// New builds the authenticators used by cfg.AuthRoutes. Each one is created
+// once and shared between routes.
+func New(cfg *config.Config) (*Guard, error) {
+	methods := make(map[string]Authenticator)
+	for _, route := range cfg.AuthRoutes {
+		for _, name := range route.Methods {
+			if _, ok := methods[name]; ok {
+				continue
+			}
+
+			var err error
+			switch name {
+			case "api_key":
+				methods[name] = NewAPIKeys(cfg.APIKeyHeader, cfg.APIKeys)
+			case "basic":
+				methods[name], err = LoadHtpasswd(cfg.HtpasswdFile)
+			case "jwt":
+				methods[name], err = NewJWT(cfg)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	g := &Guard{}
+	for _, route := range cfg.AuthRoutes {
+		r := &Route{
+			prefix: route.Prefix,
+			claims: route.Claims,
+			realm: route.Realm,
+		}
+		for _, name := range route.Methods {
+			r.methods = append(r.methods, methods[name])
+		}
+		g.routes = append(g.routes, r)
+	}
+
+	// longest prefix wins
+	sort.SliceStable(g.routes, func(i, j int) bool {
+		return len(g.routes[i].prefix) > len(g.routes[j].prefix)
+	})
+
+	return g, nil
+}
+
+// Match returns the route protecting path, or nil if it is public.
+func (g *Guard) Match(path string) *Route {
+	for _, route := range g.routes {
+		if strings.HasPrefix(path, route.prefix) {
+			return route
+		}
+	}
+	return nil
+}
+
+// Authenticate tries each method in turn. The first one that finds
+// credentials decides the outcome.
+func (rt *Route) Authenticate(r *http.Request) (*Principal, error) {
+	for _, method := range rt.methods {
+		p, err := method.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for name, want := range rt.claims {
+			if !p.HasClaim(name, want) {
+				return p, ErrForbidden
+			}
+		}
+		return p, nil
+	}
+
+	return nil, ErrNoCredentials
+}
+
+// Challenges lists the WWW-Authenticate values for the route's methods.
+func (rt *Route) Challenges() []string {
+	var challenges []string
+	for _, method := range rt.methods {
+		if c := method.Challenge(rt.realm); c != "" {
+			challenges = append(challenges, c)
+		}
+	}
+	return challenges
+}
\ No newline at end of file
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/samnart1/GoLang-Projects/005server/internal/config"
+)
+
+// JWT accepts bearer tokens signed with HS256 when a secret is configured,
+// or RS256 when a public key is. Tokens must carry an expiry.
+type JWT struct {
+	parser	*jwt.Parser
+	key		interface{}
+}
+
+func NewJWT(cfg *config.Config) (*JWT, error) {
+	j := &JWT{}
+	var method string
+
+	if cfg.JWTSecret != "" {
+		j.key = []byte(cfg.JWTSecret)
+		method = jwt.SigningMethodHS256.Alg()
+	} else {
+		data, err := os.ReadFile(cfg.JWTPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwt public key: %w", err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwt public key: %w", err)
+		}
+		j.key = key
+		method = jwt.SigningMethodRS256.Alg()
+	}
+
+	opts := []jwt.ParserOption{
+		// pinning the method stops a token choosing its own algorithm
+		jwt.WithValidMethods([]string{method}),
+		jwt.WithLeeway(cfg.JWTLeeway),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.JWTIssuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.JWTIssuer))
+	}
+	if cfg.JWTAudience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.JWTAudience))
+	}
+	j.parser = jwt.NewParser(opts...)
+
+	return j, nil
+}
+
+func (j *JWT) Authenticate(r *http.Request) (*Principal, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, ErrNoCredentials
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := j.parser.ParseWithClaims(strings.TrimSpace(token), claims, func(*jwt.Token) (interface{}, error) {
+		return j.key, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	subject, _ := claims.GetSubject()
+	return &Principal{
+		Subject: subject,
+		Method: "jwt",
+		Claims: claims,
+	}, nil
+}
+
+func (j *JWT) Challenge(realm string) string {
+	return fmt.Sprintf("Bearer realm=%q", realm)
+}
\ No newline at end of file
@@ -0,0 +1,108 @@
+package certs
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// Options describe the TLS policy of the server.
+type Options struct {
+	MinVersion		string
+	CipherPolicy	string
+	ClientAuth		string
+	HTTP2			bool
+}
+
+// intermediateSuites are the forward-secret AEAD suites for TLS 1.2. TLS 1.3
+// suites are not configurable and are always enabled.
+var intermediateSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// TLSConfig builds a server configuration that takes its certificate and
+// client CAs from r, so reloads apply to every new handshake.
+func TLSConfig(r *Reloader, opts Options) (*tls.Config, error) {
+	minVersion, err := parseVersion(opts.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		MinVersion: minVersion,
+		GetCertificate: r.GetCertificate,
+	}
+
+	switch strings.ToLower(opts.CipherPolicy) {
+	case "", "intermediate":
+		cfg.CipherSuites = intermediateSuites
+	case "modern":
+		cfg.MinVersion = tls.VersionTLS13
+	case "compatible":
+		// Go's defaults
+	default:
+		return nil, fmt.Errorf("unknown TLS cipher policy: %s", opts.CipherPolicy)
+	}
+
+	if opts.HTTP2 {
+		cfg.NextProtos = []string{"h2", "http/1.1"}
+	} else {
+		cfg.NextProtos = []string{"http/1.1"}
+	}
+
+	if r.ClientCAs() == nil {
+		return cfg, nil
+	}
+
+	clientAuth, err := parseClientAuth(opts.ClientAuth)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ClientAuth = clientAuth
+
+	// the CA pool is looked up per handshake so a reloaded bundle applies
+	// without restarting
+	base := cfg.Clone()
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c := base.Clone()
+		c.ClientCAs = r.ClientCAs()
+		return c, nil
+	}
+
+	return cfg, nil
+}
+
+func parseVersion(v string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToLower(v), "tls") {
+	case "1.0", "10":
+		return tls.VersionTLS10, nil
+	case "1.1", "11":
+		return tls.VersionTLS11, nil
+	case "", "1.2", "12":
+		return tls.VersionTLS12, nil
+	case "1.3", "13":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unknown TLS version: %s", v)
+	}
+}
+
+func parseClientAuth(mode string) (tls.ClientAuthType, error) {
+	switch strings.ToLower(mode) {
+	case "", "require_and_verify":
+		return tls.RequireAndVerifyClientCert, nil
+	case "verify_if_given", "optional":
+		return tls.VerifyClientCertIfGiven, nil
+	case "request":
+		return tls.RequestClientCert, nil
+	case "none":
+		return tls.NoClientCert, nil
+	default:
+		return 0, fmt.Errorf("unknown TLS client auth mode: %s", mode)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,197 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/samnart1/GoLang-Projects/005server/internal/logger"
+)
+
+// Reloader keeps the server certificate and the client CA bundle in memory
+// and swaps them when the files on disk change. Handshakes in progress keep
+// the material they started with, so reloading never drops connections.
+type Reloader struct {
+	certFile	string
+	keyFile		string
+	caFile		string
+	logger		*logger.Logger
+
+	mu			sync.RWMutex
+	cert		*tls.Certificate
+	clientCAs	*x509.CertPool
+
+	watcher		*fsnotify.Watcher
+	done		chan struct{}
+}
+
+func NewReloader(certFile, keyFile, caFile string, log *logger.Logger) (*Reloader, error) {
+	r := &Reloader{
+		certFile: certFile,
+		keyFile: keyFile,
+		caFile: caFile,
+		logger: log,
+		done: make(chan struct{}),
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Bundle is certificate material loaded from disk but not yet served.
+type Bundle struct {
+	cert		*tls.Certificate
+	clientCAs	*x509.CertPool
+}
+
+// Reload reads the certificate, key and CA bundle from disk. The current
+// material is kept if any of them fail to load.
+func (r *Reloader) Reload() error {
+	bundle, err := r.Load()
+	if err != nil {
+		return err
+	}
+
+	r.Use(bundle)
+	return nil
+}
+
+// Load reads the certificate, key and CA bundle from disk without serving
+// them yet.
+func (r *Reloader) Load() (*Bundle, error) {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate: %w", err)
+	}
+
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		data, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA bundle: %w", err)
+		}
+
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in client CA bundle %s", r.caFile)
+		}
+	}
+
+	return &Bundle{cert: &cert, clientCAs: pool}, nil
+}
+
+// Use starts serving the material in bundle to new handshakes.
+func (r *Reloader) Use(bundle *Bundle) {
+	r.mu.Lock()
+	r.cert = bundle.cert
+	r.clientCAs = bundle.clientCAs
+	r.mu.Unlock()
+}
+
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+func (r *Reloader) ClientCAs() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.clientCAs
+}
+
+// Watch reloads the files whenever they change on disk. The parent
+// directories are watched rather than the files, since certificate tooling
+// usually replaces files by renaming new ones into place.
+func (r *Reloader) Watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	files := map[string]bool{}
+	dirs := map[string]bool{}
+	for _, file := range []string{r.certFile, r.keyFile, r.caFile} {
+		if file == "" {
+			continue
+		}
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			watcher.Close()
+			return err
+		}
+		files[abs] = true
+		dirs[filepath.Dir(abs)] = true
+	}
+
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return err
+		}
+	}
+
+	r.watcher = watcher
+	go r.watchLoop(files)
+
+	return nil
+}
+
+func (r *Reloader) Close() error {
+	if r.watcher == nil {
+		return nil
+	}
+
+	close(r.done)
+	return r.watcher.Close()
+}
+
+func (r *Reloader) watchLoop(files map[string]bool) {
+	// a renewal usually rewrites the cert and key one after the other
+	const settle = 500 * time.Millisecond
+
+	var timer *time.Timer
+	var pending <-chan time.Time
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case event, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			if !files[filepath.Clean(event.Name)] {
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(settle)
+			} else {
+				timer.Reset(settle)
+			}
+			pending = timer.C
+		case <-pending:
+			pending = nil
+			if err := r.Reload(); err != nil {
+				r.logger.Error("Certificate reload failed", "error", err)
+				continue
+			}
+			r.logger.Info("Certificates reloaded", "cert", r.certFile)
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			r.logger.Error("Certificate watcher error", "error", err)
+		}
+	}
+}
\ No newline at end of file
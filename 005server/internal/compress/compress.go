@@ -0,0 +1,117 @@
+package compress
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Encodings are the supported content codings in default order of
+// preference.
+var Encodings = []string{"br", "gzip", "deflate"}
+
+// DefaultTypes are the media types worth compressing. Entries may be a full
+// type, "text/*" for a whole top-level type or "*+json" for a suffix.
+var DefaultTypes = []string{
+	"text/*",
+	"*+xml",
+	"*+json",
+	"application/javascript",
+	"application/json",
+	"application/xml",
+	"image/svg+xml",
+	"application/wasm",
+}
+
+// Accepted parses Accept-Encoding, dropping codings with q=0. A "*" accepts
+// every one of known that is not listed explicitly.
+func Accepted(header string, known []string) map[string]bool {
+	accepted := make(map[string]bool)
+
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+
+		q := 1.0
+		if k, v, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(k) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				q = parsed
+			}
+		}
+
+		accepted[coding] = q > 0
+	}
+
+	if accepted["*"] {
+		for _, name := range known {
+			if _, ok := accepted[name]; !ok {
+				accepted[name] = true
+			}
+		}
+	}
+
+	return accepted
+}
+
+// Negotiate returns the first of prefs the client accepts, or "".
+func Negotiate(header string, prefs []string) string {
+	if header == "" {
+		return ""
+	}
+
+	accepted := Accepted(header, prefs)
+	for _, name := range prefs {
+		if accepted[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// Types matches media types against a list in the DefaultTypes format.
+type Types []string
+
+func (t Types) Match(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if mediaType == "" {
+		return false
+	}
+
+	for _, pattern := range t {
+		switch {
+		case strings.HasSuffix(pattern, "/*"):
+			if strings.HasPrefix(mediaType, strings.TrimSuffix(pattern, "*")) {
+				return true
+			}
+		case strings.HasPrefix(pattern, "*"):
+			if strings.HasSuffix(mediaType, strings.TrimPrefix(pattern, "*")) {
+				return true
+			}
+		case mediaType == pattern:
+			return true
+		}
+	}
+	return false
+}
+
+// NewWriter returns an encoder for the content coding. Flushing it must not
+// wait for more input, which all three formats support.
+func NewWriter(w io.Writer, encoding string) io.WriteCloser {
+	switch encoding {
+	case "br":
+		return brotli.NewWriterLevel(w, brotli.DefaultCompression)
+	case "deflate":
+		// the http deflate coding is the zlib format, not raw deflate
+		return zlib.NewWriter(w)
+	default:
+		return gzip.NewWriter(w)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,192 @@
+package compress
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type Options struct {
+	// MinSize is the smallest body that is compressed. Smaller bodies are
+	// held back until the handler finishes or flushes.
+	MinSize		int
+	Types		Types
+	// Encodings lists the codings to offer, most preferred first.
+	Encodings	[]string
+}
+
+// Handler compresses responses from next. Responses that already carry a
+// Content-Encoding, such as precompressed static files, pass through.
+func Handler(next http.Handler, opts Options) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cw := &writer{
+			ResponseWriter: w,
+			opts: opts,
+			encoding: Negotiate(r.Header.Get("Accept-Encoding"), opts.Encodings),
+			head: r.Method == http.MethodHead,
+		}
+		defer cw.Close()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+type writer struct {
+	http.ResponseWriter
+	opts		Options
+	encoding	string
+	head		bool
+
+	status		int
+	// decided is set once the response is known to be compressed or not
+	decided		bool
+	buf			[]byte
+	zw			io.WriteCloser
+}
+
+func (w *writer) WriteHeader(code int) {
+	if w.status != 0 {
+		return
+	}
+	// informational responses go straight out and do not count
+	if code >= 100 && code < 200 {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+
+	header := w.Header()
+	if w.opts.Types.Match(header.Get("Content-Type")) && !varies(header, "Accept-Encoding") {
+		header.Add("Vary", "Accept-Encoding")
+	}
+
+	if !w.eligible() {
+		w.passthrough()
+	}
+}
+
+// eligible reports whether the response could be compressed, before its
+// size is known.
+func (w *writer) eligible() bool {
+	header := w.Header()
+	switch {
+	case w.encoding == "", w.head:
+		return false
+	case w.status < 200, w.status == http.StatusNoContent, w.status == http.StatusNotModified:
+		return false
+	case w.status == http.StatusPartialContent, header.Get("Content-Range") != "":
+		return false
+	case header.Get("Content-Encoding") != "":
+		return false
+	}
+	return w.opts.Types.Match(header.Get("Content-Type"))
+}
+
+func (w *writer) passthrough() {
+	w.decided = true
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func (w *writer) start() {
+	w.decided = true
+
+	header := w.Header()
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+	// the encoded bytes differ, so a strong validator from the handler
+	// only holds weakly; If-None-Match compares weakly and still matches
+	if etag := header.Get("ETag"); strings.HasPrefix(etag, `"`) {
+		header.Set("ETag", "W/"+etag)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	w.zw = NewWriter(w.ResponseWriter, w.encoding)
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+
+	switch {
+	case w.zw != nil:
+		return w.zw.Write(p)
+	case w.decided:
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.opts.MinSize {
+		w.start()
+		if _, err := w.zw.Write(w.buf); err != nil {
+			return 0, err
+		}
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Flush compresses whatever is held back, since a flushing handler is
+// streaming and its total size is unknown.
+func (w *writer) Flush() {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.decided {
+		w.start()
+		w.zw.Write(w.buf)
+		w.buf = nil
+	}
+
+	if f, ok := w.zw.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Close sends a body that stayed under MinSize as is, or finishes the
+// compressed stream.
+func (w *writer) Close() error {
+	if w.status == 0 {
+		// the handler wrote nothing
+		return nil
+	}
+
+	if !w.decided {
+		w.passthrough()
+		_, err := w.ResponseWriter.Write(w.buf)
+		return err
+	}
+
+	if w.zw != nil {
+		return w.zw.Close()
+	}
+	return nil
+}
+
+// Hijack lets websocket upgrades through the wrapper, provided the writers
+// beneath it unwrap down to the connection.
+func (w *writer) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *writer) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func varies(header http.Header, name string) bool {
+	for _, value := range header.Values("Vary") {
+		for _, field := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(field), name) {
+				return true
+			}
+		}
+	}
+	return false
+}
\ No newline at end of file
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// AuthRoute requires one of Methods to authenticate every path starting
+// with Prefix. Claims must all be present on the principal.
+type AuthRoute struct {
+	Prefix	string				`json:"prefix"`
+	Methods	[]string			`json:"methods"`
+	Claims	map[string]string	`json:"claims"`
+	Realm	string				`json:"realm"`
+}
+
+// APIKey is a named static key, stored as the hex SHA-256 of the key.
+type APIKey struct {
+	Name	string	`json:"name"`
+	Hash	string	`json:"-"`
+}
+
+var authMethods = map[string]bool{"api_key": true, "basic": true, "jwt": true}
+
+// parseAuthRoutes parses semicolon separated routes of the form
+//
+//	/api/echo -> api_key,jwt claim=scope:write
+//
+// Methods are tried in order. Options are claim=name:value, which may be
+// repeated, and realm for the Basic auth challenge. A trailing * on the
+// prefix is optional.
+func parseAuthRoutes(value string) ([]AuthRoute, error) {
+	var routes []AuthRoute
+
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		pattern, rest, ok := strings.Cut(entry, "->")
+		if !ok {
+			return nil, fmt.Errorf("expected 'prefix -> methods', got %q", entry)
+		}
+
+		prefix := strings.TrimSuffix(strings.TrimSpace(pattern), "*")
+		if !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("route prefix must start with /: %q", pattern)
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("route %s has no methods", prefix)
+		}
+
+		route := AuthRoute{Prefix: prefix, Realm: "restricted"}
+		for _, method := range strings.Split(fields[0], ",") {
+			if !authMethods[method] {
+				return nil, fmt.Errorf("route %s: unknown auth method %q", prefix, method)
+			}
+			route.Methods = append(route.Methods, method)
+		}
+
+		for _, opt := range fields[1:] {
+			key, val, ok := strings.Cut(opt, "=")
+			if !ok {
+				return nil, fmt.Errorf("route %s: expected key=value, got %q", prefix, opt)
+			}
+
+			switch key {
+			case "claim":
+				name, want, ok := strings.Cut(val, ":")
+				if !ok || name == "" {
+					return nil, fmt.Errorf("route %s: expected name:value in %q", prefix, opt)
+				}
+				if route.Claims == nil {
+					route.Claims = map[string]string{}
+				}
+				route.Claims[name] = want
+			case "realm":
+				route.Realm = val
+			default:
+				return nil, fmt.Errorf("route %s: unknown option %q", prefix, key)
+			}
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+// parseAPIKeys parses a comma separated list of name:sha256hex pairs.
+func parseAPIKeys(list []string) ([]APIKey, error) {
+	var keys []APIKey
+	for _, item := range list {
+		name, hash, ok := strings.Cut(item, ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("expected name:sha256, got %q", item)
+		}
+
+		hash = strings.ToLower(hash)
+		if b, err := hex.DecodeString(hash); err != nil || len(b) != 32 {
+			return nil, fmt.Errorf("key %s is not a hex sha256 hash", name)
+		}
+		keys = append(keys, APIKey{Name: name, Hash: hash})
+	}
+	return keys, nil
+}
+
+func (c *Config) usesAuthMethod(method string) bool {
+	for _, route := range c.AuthRoutes {
+		for _, m := range route.Methods {
+			if m == method {
+				return true
+			}
+		}
+	}
+	return false
+}
\ No newline at end of file
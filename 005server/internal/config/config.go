@@ -3,10 +3,11 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"time"
 
-	"github.com/joho/godotenv"
+	"github.com/samnart1/GoLang-Projects/005server/internal/compress"
 )
 
 type Config struct {
@@ -17,15 +18,54 @@ type Config struct {
 	WriteTimeout 	time.Duration	`json:"write_timeout"`
 	IdleTimeout 	time.Duration	`json:"idle_timeout"`
 	ShutdownTimeout time.Duration	`json:"shutdown_timeout"`
-	EnableCORS 		bool			`json:"enable_cors"`
+	CORS			CORSPolicy		`json:"cors"`
+	CORSRoutes		[]CORSRoute		`json:"cors_routes"`
+	Security		SecurityHeaders	`json:"security_headers"`
 	EnableRateLimit bool			`json:"enable_rate_limit"`
 	RateLimitRPS 	int				`json:"rate_limit_rps"`
+	RateLimitBurst	int				`json:"rate_limit_burst"`
+	// RateLimitHeader keys clients by this header, when a trusted proxy sets it
+	RateLimitHeader	string			`json:"rate_limit_header"`
+	RateLimitIdle	time.Duration	`json:"rate_limit_idle"`
+	RateLimitRoutes	[]RouteLimit	`json:"rate_limit_routes"`
+	TrustedProxies	[]string		`json:"trusted_proxies"`
+	EnableMetrics	bool			`json:"enable_metrics"`
+	HealthTimeout	time.Duration	`json:"health_timeout"`
 	StaticDir		string			`json:"static_dir"`
+	StaticMaxAge	time.Duration	`json:"static_max_age"`
+	StaticListing	bool			`json:"static_listing"`
+	StaticCompress	bool			`json:"static_compress"`
 	TemplateDir		string			`json:"template_dir"`
+	TemplateReload	bool			`json:"template_reload"`
+	TLSCertFile		string			`json:"tls_cert_file"`
+	TLSKeyFile		string			`json:"tls_key_file"`
+	TLSMinVersion	string			`json:"tls_min_version"`
+	TLSCipherPolicy	string			`json:"tls_cipher_policy"`
+	TLSClientCA		string			`json:"tls_client_ca"`
+	TLSClientAuth	string			`json:"tls_client_auth"`
+	EnableHTTP2		bool			`json:"enable_http2"`
+	RedirectPort	string			`json:"redirect_port"`
+	ProxyRoutes		[]ProxyRoute	`json:"proxy_routes"`
+	ProxyHealthPath	string			`json:"proxy_health_path"`
+	ProxyHealthInterval	time.Duration	`json:"proxy_health_interval"`
+	EnableCompression	bool		`json:"enable_compression"`
+	CompressionMinSize	int			`json:"compression_min_size"`
+	CompressionTypes	[]string	`json:"compression_types"`
+	CompressionEncodings	[]string	`json:"compression_encodings"`
+	EnableETags		bool			`json:"enable_etags"`
+	AuthRoutes		[]AuthRoute		`json:"auth_routes"`
+	APIKeys			[]APIKey		`json:"api_keys"`
+	APIKeyHeader	string			`json:"api_key_header"`
+	HtpasswdFile	string			`json:"htpasswd_file"`
+	JWTSecret		string			`json:"-"`
+	JWTPublicKeyFile	string		`json:"jwt_public_key_file"`
+	JWTIssuer		string			`json:"jwt_issuer"`
+	JWTAudience		string			`json:"jwt_audience"`
+	JWTLeeway		time.Duration	`json:"jwt_leeway"`
 }
 
 func Load() (*Config, error) {
-	_ = godotenv.Load()
+	loadDotEnv()
 
 	cfg := &Config{
 		Port: getEnv("PORT", "8080"),
@@ -35,11 +75,82 @@ func Load() (*Config, error) {
 		WriteTimeout: getDurationEnv("WRITE_TIMEOUT", 15*time.Second),
 		IdleTimeout: getDurationEnv("IDLE_TIMEOUT", 60*time.Second),
 		ShutdownTimeout: getDurationEnv("SHUTDOWN_TIMEOUT", 30*time.Second),
-		EnableCORS: getBoolEnv("ENABLE_CORS", true),
+		CORS: loadCORSPolicy(),
+		Security: loadSecurityHeaders(),
 		EnableRateLimit: getBoolEnv("ENABLE_RATE_LIMIT", false),
 		RateLimitRPS: getIntEnv("RATE_LIMIT_RPS", 100),
+		RateLimitBurst: getIntEnv("RATE_LIMIT_BURST", 0),
+		RateLimitHeader: getEnv("RATE_LIMIT_HEADER", ""),
+		RateLimitIdle: getDurationEnv("RATE_LIMIT_IDLE", 10*time.Minute),
+		TrustedProxies: getListEnv("TRUSTED_PROXIES"),
+		// /metrics exposes traffic and runtime details, so it is opt in; put it
+		// behind AUTH_ROUTES when enabling it on a public address
+		EnableMetrics: getBoolEnv("ENABLE_METRICS", false),
+		HealthTimeout: getDurationEnv("HEALTH_TIMEOUT", 2*time.Second),
 		StaticDir: getEnv("STATIC_DIR", "./web/static"),
+		StaticMaxAge: getDurationEnv("STATIC_MAX_AGE", time.Hour),
+		StaticListing: getBoolEnv("STATIC_LISTING", false),
+		StaticCompress: getBoolEnv("STATIC_COMPRESS", true),
 		TemplateDir: getEnv("TEMPLATE_DIR", "./web/templates"),
+		TLSCertFile: getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile: getEnv("TLS_KEY_FILE", ""),
+		TLSMinVersion: getEnv("TLS_MIN_VERSION", "1.2"),
+		TLSCipherPolicy: getEnv("TLS_CIPHER_POLICY", "intermediate"),
+		TLSClientCA: getEnv("TLS_CLIENT_CA", ""),
+		TLSClientAuth: getEnv("TLS_CLIENT_AUTH", "require_and_verify"),
+		EnableHTTP2: getBoolEnv("ENABLE_HTTP2", true),
+		RedirectPort: getEnv("HTTP_REDIRECT_PORT", ""),
+		ProxyHealthPath: getEnv("PROXY_HEALTH_PATH", "/health"),
+		ProxyHealthInterval: getDurationEnv("PROXY_HEALTH_INTERVAL", 10*time.Second),
+		EnableCompression: getBoolEnv("ENABLE_COMPRESSION", true),
+		CompressionMinSize: getIntEnv("COMPRESSION_MIN_SIZE", 1024),
+		CompressionTypes: getListEnvDefault("COMPRESSION_TYPES", compress.DefaultTypes),
+		CompressionEncodings: getListEnvDefault("COMPRESSION_ENCODINGS", compress.Encodings),
+		EnableETags: getBoolEnv("ENABLE_ETAGS", true),
+		APIKeyHeader: getEnv("AUTH_API_KEY_HEADER", "X-API-Key"),
+		HtpasswdFile: getEnv("AUTH_HTPASSWD_FILE", ""),
+		JWTSecret: getEnv("AUTH_JWT_SECRET", ""),
+		JWTPublicKeyFile: getEnv("AUTH_JWT_PUBLIC_KEY_FILE", ""),
+		JWTIssuer: getEnv("AUTH_JWT_ISSUER", ""),
+		JWTAudience: getEnv("AUTH_JWT_AUDIENCE", ""),
+		JWTLeeway: getDurationEnv("AUTH_JWT_LEEWAY", 30*time.Second),
+	}
+
+	// templates are reloaded on change while developing unless told otherwise
+	cfg.TemplateReload = getBoolEnv("TEMPLATE_RELOAD", !cfg.IsProduction())
+
+	routes, err := parseRouteLimits(getEnv("RATE_LIMIT_ROUTES", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_ROUTES: %w", err)
+	}
+	cfg.RateLimitRoutes = routes
+
+	proxyRoutes, err := parseProxyRoutes(getEnv("PROXY_ROUTES", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROXY_ROUTES: %w", err)
+	}
+	cfg.ProxyRoutes = proxyRoutes
+
+	corsRoutes, err := parseCORSRoutes(getEnv("CORS_ROUTES", ""), cfg.CORS)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CORS_ROUTES: %w", err)
+	}
+	cfg.CORSRoutes = corsRoutes
+
+	authRoutes, err := parseAuthRoutes(getEnv("AUTH_ROUTES", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_ROUTES: %w", err)
+	}
+	cfg.AuthRoutes = authRoutes
+
+	apiKeys, err := parseAPIKeys(getListEnv("AUTH_API_KEYS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_API_KEYS: %w", err)
+	}
+	cfg.APIKeys = apiKeys
+
+	if cfg.RateLimitBurst <= 0 {
+		cfg.RateLimitBurst = cfg.RateLimitRPS
 	}
 
 	if err := cfg.validate(); err != nil {
@@ -66,6 +177,40 @@ func (c *Config) validate() error {
 		return fmt.Errorf("write timeout must be positive")
 	}
 
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("tls cert file and key file must be set together")
+	}
+
+	if !c.TLSEnabled() && (c.TLSClientCA != "" || c.RedirectPort != "") {
+		return fmt.Errorf("tls client ca and http redirect port require a tls cert and key")
+	}
+
+	if err := c.CORS.validate(); err != nil {
+		return err
+	}
+
+	if c.usesAuthMethod("api_key") && len(c.APIKeys) == 0 {
+		return fmt.Errorf("api_key auth requires AUTH_API_KEYS")
+	}
+
+	if c.usesAuthMethod("basic") && c.HtpasswdFile == "" {
+		return fmt.Errorf("basic auth requires AUTH_HTPASSWD_FILE")
+	}
+
+	if c.usesAuthMethod("jwt") && (c.JWTSecret == "") == (c.JWTPublicKeyFile == "") {
+		return fmt.Errorf("jwt auth requires exactly one of AUTH_JWT_SECRET and AUTH_JWT_PUBLIC_KEY_FILE")
+	}
+
+	for _, encoding := range c.CompressionEncodings {
+		if !slices.Contains(compress.Encodings, encoding) {
+			return fmt.Errorf("unknown compression encoding %q", encoding)
+		}
+	}
+
+	if c.EnableRateLimit && c.RateLimitRPS <= 0 {
+		return fmt.Errorf("rate limit rps must be positive when rate limiting is enabled")
+	}
+
 	return nil
 }
 
@@ -73,6 +218,10 @@ func (c *Config) IsProduction() bool {
 	return c.Environment == "production"
 }
 
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if parsed, err := time.ParseDuration(value); err == nil {
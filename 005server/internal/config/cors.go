@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy decides which cross-origin requests browsers may make. An
+// origin of "*" allows any origin, and "https://*.example.com" allows any
+// subdomain of example.com over https.
+type CORSPolicy struct {
+	Enabled				bool			`json:"enabled"`
+	AllowedOrigins		[]string		`json:"allowed_origins"`
+	AllowedMethods		[]string		`json:"allowed_methods"`
+	AllowedHeaders		[]string		`json:"allowed_headers"`
+	ExposedHeaders		[]string		`json:"exposed_headers"`
+	AllowCredentials	bool			`json:"allow_credentials"`
+	MaxAge				time.Duration	`json:"max_age"`
+}
+
+// CORSRoute overrides the default policy for every path starting with Prefix.
+type CORSRoute struct {
+	Prefix	string		`json:"prefix"`
+	Policy	CORSPolicy	`json:"policy"`
+}
+
+// SecurityHeaders are sent on every response. An empty value leaves that
+// header out; HSTS is only sent over TLS.
+type SecurityHeaders struct {
+	Enabled					bool			`json:"enabled"`
+	ContentSecurityPolicy	string			`json:"content_security_policy"`
+	FrameOptions			string			`json:"frame_options"`
+	ReferrerPolicy			string			`json:"referrer_policy"`
+	HSTSMaxAge				time.Duration	`json:"hsts_max_age"`
+	HSTSIncludeSubdomains	bool			`json:"hsts_include_subdomains"`
+	HSTSPreload				bool			`json:"hsts_preload"`
+}
+
+func loadCORSPolicy() CORSPolicy {
+	return CORSPolicy{
+		Enabled: getBoolEnv("ENABLE_CORS", true),
+		AllowedOrigins: getListEnvDefault("CORS_ALLOWED_ORIGINS", []string{"*"}),
+		AllowedMethods: getListEnvDefault("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
+		AllowedHeaders: getListEnvDefault("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", "X-Requested-With", "X-Request-ID", "traceparent"}),
+		ExposedHeaders: getListEnvDefault("CORS_EXPOSED_HEADERS", []string{"X-Request-ID", "traceparent"}),
+		AllowCredentials: getBoolEnv("CORS_ALLOW_CREDENTIALS", false),
+		MaxAge: getDurationEnv("CORS_MAX_AGE", 24*time.Hour),
+	}
+}
+
+func loadSecurityHeaders() SecurityHeaders {
+	return SecurityHeaders{
+		Enabled: getBoolEnv("SECURITY_HEADERS", true),
+		ContentSecurityPolicy: getEnv("SECURITY_CSP", "default-src 'self'; img-src 'self' data:; object-src 'none'; base-uri 'self'; frame-ancestors 'none'"),
+		FrameOptions: getEnv("SECURITY_FRAME_OPTIONS", "DENY"),
+		ReferrerPolicy: getEnv("SECURITY_REFERRER_POLICY", "strict-origin-when-cross-origin"),
+		HSTSMaxAge: getDurationEnv("SECURITY_HSTS_MAX_AGE", 365*24*time.Hour),
+		HSTSIncludeSubdomains: getBoolEnv("SECURITY_HSTS_INCLUDE_SUBDOMAINS", false),
+		HSTSPreload: getBoolEnv("SECURITY_HSTS_PRELOAD", false),
+	}
+}
+
+// parseCORSRoutes parses semicolon separated routes of the form
+//
+//	/api/admin/* -> origins=https://admin.example.com credentials=true
+//
+// Each route starts from base. Options are enabled, origins, methods,
+// headers, expose, credentials and max_age; list values are comma separated.
+func parseCORSRoutes(value string, base CORSPolicy) ([]CORSRoute, error) {
+	var routes []CORSRoute
+
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		pattern, rest, ok := strings.Cut(entry, "->")
+		if !ok {
+			return nil, fmt.Errorf("expected 'prefix -> options', got %q", entry)
+		}
+
+		prefix := strings.TrimSuffix(strings.TrimSpace(pattern), "*")
+		if !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("route prefix must start with /: %q", pattern)
+		}
+
+		route := CORSRoute{Prefix: prefix, Policy: base}
+		for _, opt := range strings.Fields(rest) {
+			if err := route.Policy.setOption(opt); err != nil {
+				return nil, fmt.Errorf("route %s: %w", prefix, err)
+			}
+		}
+
+		if err := route.Policy.validate(); err != nil {
+			return nil, fmt.Errorf("route %s: %w", prefix, err)
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+func (p *CORSPolicy) setOption(opt string) error {
+	key, value, ok := strings.Cut(opt, "=")
+	if !ok {
+		return fmt.Errorf("expected key=value, got %q", opt)
+	}
+
+	var err error
+	switch key {
+	case "enabled":
+		p.Enabled, err = strconv.ParseBool(value)
+	case "origins":
+		p.AllowedOrigins = splitList(value)
+	case "methods":
+		p.AllowedMethods = splitList(value)
+	case "headers":
+		p.AllowedHeaders = splitList(value)
+	case "expose":
+		p.ExposedHeaders = splitList(value)
+	case "credentials":
+		p.AllowCredentials, err = strconv.ParseBool(value)
+	case "max_age":
+		p.MaxAge, err = time.ParseDuration(value)
+	default:
+		return fmt.Errorf("unknown option %q", key)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return nil
+}
+
+func (p *CORSPolicy) validate() error {
+	if !p.Enabled || !p.AllowCredentials {
+		return nil
+	}
+
+	// browsers reject credentialed responses for a wildcard origin
+	for _, origin := range p.AllowedOrigins {
+		if origin == "*" {
+			return fmt.Errorf("cors credentials cannot be allowed for origin *")
+		}
+	}
+	return nil
+}
\ No newline at end of file
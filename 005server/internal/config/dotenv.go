@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// processEnv is the environment the process was started with, before any
+// .env file was applied. Those variables always win over the file.
+var processEnv = os.Environ()
+
+// dotenvKeys are the variables that were set from the .env file, so a reload
+// can drop the ones that have since been removed from it.
+var dotenvKeys = map[string]bool{}
+
+// loadDotEnv applies the .env file on top of the process environment. Unlike
+// godotenv.Load it can be called again to pick up edits to the file.
+func loadDotEnv() {
+	values, err := godotenv.Read()
+	if err != nil {
+		values = nil
+	}
+
+	for key := range dotenvKeys {
+		if _, ok := values[key]; !ok {
+			os.Unsetenv(key)
+			delete(dotenvKeys, key)
+		}
+	}
+
+	for key, value := range values {
+		if inProcessEnv(key) {
+			continue
+		}
+		os.Setenv(key, value)
+		dotenvKeys[key] = true
+	}
+}
+
+func inProcessEnv(key string) bool {
+	prefix := key + "="
+	for _, kv := range processEnv {
+		if strings.HasPrefix(kv, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProcessEnv returns the environment the process was started with, without
+// the values read from the .env file.
+func ProcessEnv() []string {
+	return append([]string(nil), processEnv...)
+}
\ No newline at end of file
@@ -1 +1,76 @@
-package config
\ No newline at end of file
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// RouteLimit overrides the default rate limit for every path starting with
+// Prefix. An RPS of zero exempts the prefix from rate limiting.
+type RouteLimit struct {
+	Prefix	string	`json:"prefix"`
+	RPS		int		`json:"rps"`
+	Burst	int		`json:"burst"`
+}
+
+func getListEnv(key string) []string {
+	return splitList(os.Getenv(key))
+}
+
+func getListEnvDefault(key string, defaultValue []string) []string {
+	if _, ok := os.LookupEnv(key); !ok {
+		return defaultValue
+	}
+	return getListEnv(key)
+}
+
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// parseRouteLimits parses a comma separated list of prefix=rps[:burst]
+// entries, e.g. "/api/echo=5:10,/static/=0".
+func parseRouteLimits(value string) ([]RouteLimit, error) {
+	var limits []RouteLimit
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		prefix, spec, ok := strings.Cut(entry, "=")
+		if !ok || prefix == "" {
+			return nil, fmt.Errorf("expected prefix=rps[:burst], got %q", entry)
+		}
+
+		rpsStr, burstStr, hasBurst := strings.Cut(spec, ":")
+		rps, err := strconv.Atoi(rpsStr)
+		if err != nil || rps < 0 {
+			return nil, fmt.Errorf("invalid rps in %q", entry)
+		}
+
+		burst := rps
+		if hasBurst {
+			if burst, err = strconv.Atoi(burstStr); err != nil || burst < 0 {
+				return nil, fmt.Errorf("invalid burst in %q", entry)
+			}
+		}
+
+		limits = append(limits, RouteLimit{
+			Prefix: strings.TrimSpace(prefix),
+			RPS: rps,
+			Burst: burst,
+		})
+	}
+
+	return limits, nil
+}
\ No newline at end of file
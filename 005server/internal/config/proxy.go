@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProxyRoute forwards every request under Prefix to one of Targets.
+type ProxyRoute struct {
+	Prefix				string				`json:"prefix"`
+	Targets				[]string			`json:"targets"`
+	Balance				string				`json:"balance"`
+	// Timeout bounds connecting to an upstream and waiting for its headers
+	Timeout				time.Duration		`json:"timeout"`
+	Retries				int					`json:"retries"`
+	StripPrefix			bool				`json:"strip_prefix"`
+	HealthPath			string				`json:"health_path"`
+	SetHeaders			map[string]string	`json:"set_headers"`
+	RemoveHeaders		[]string			`json:"remove_headers"`
+	SetResponseHeaders	map[string]string	`json:"set_response_headers"`
+}
+
+// parseProxyRoutes parses semicolon separated routes of the form
+//
+//	/api/users/* -> http://127.0.0.1:9001,9002 balance=least_conn timeout=5s
+//
+// A target that is only a port reuses the scheme and host of the one before
+// it. Options are balance, timeout, retries, strip_prefix, health,
+// set_header=Name:value, remove_header=Name and set_response_header=Name:value;
+// the header options may be repeated and their values cannot contain spaces.
+func parseProxyRoutes(value string) ([]ProxyRoute, error) {
+	var routes []ProxyRoute
+
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		pattern, rest, ok := strings.Cut(entry, "->")
+		if !ok {
+			return nil, fmt.Errorf("expected 'prefix -> targets', got %q", entry)
+		}
+
+		prefix := strings.TrimSuffix(strings.TrimSpace(pattern), "*")
+		if !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("route prefix must start with /: %q", pattern)
+		}
+		if !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("route %s has no targets", prefix)
+		}
+
+		targets, err := parseTargets(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", prefix, err)
+		}
+
+		route := ProxyRoute{
+			Prefix: prefix,
+			Targets: targets,
+			Balance: "round_robin",
+			Timeout: 30 * time.Second,
+		}
+
+		for _, opt := range fields[1:] {
+			if err := route.setOption(opt); err != nil {
+				return nil, fmt.Errorf("route %s: %w", prefix, err)
+			}
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+func parseTargets(list string) ([]string, error) {
+	var targets []string
+	var last *url.URL
+
+	for _, target := range strings.Split(list, ",") {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+
+		if _, err := strconv.Atoi(target); err == nil {
+			if last == nil {
+				return nil, fmt.Errorf("port-only target %s needs a full target before it", target)
+			}
+			u := *last
+			u.Host = u.Hostname() + ":" + target
+			targets = append(targets, u.String())
+			continue
+		}
+
+		u, err := url.Parse(target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid target %q", target)
+		}
+		last = u
+		targets = append(targets, u.String())
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no targets")
+	}
+	return targets, nil
+}
+
+func (r *ProxyRoute) setOption(opt string) error {
+	key, value, ok := strings.Cut(opt, "=")
+	if !ok {
+		return fmt.Errorf("expected key=value, got %q", opt)
+	}
+
+	var err error
+	switch key {
+	case "balance":
+		if value != "round_robin" && value != "least_conn" {
+			return fmt.Errorf("unknown balance mode %q", value)
+		}
+		r.Balance = value
+	case "timeout":
+		r.Timeout, err = time.ParseDuration(value)
+	case "retries":
+		r.Retries, err = strconv.Atoi(value)
+	case "strip_prefix":
+		r.StripPrefix, err = strconv.ParseBool(value)
+	case "health":
+		r.HealthPath = value
+	case "set_header", "set_response_header":
+		name, val, ok := strings.Cut(value, ":")
+		if !ok || name == "" {
+			return fmt.Errorf("expected Name:value in %q", opt)
+		}
+		if key == "set_header" {
+			if r.SetHeaders == nil {
+				r.SetHeaders = map[string]string{}
+			}
+			r.SetHeaders[name] = val
+		} else {
+			if r.SetResponseHeaders == nil {
+				r.SetResponseHeaders = map[string]string{}
+			}
+			r.SetResponseHeaders[name] = val
+		}
+	case "remove_header":
+		r.RemoveHeaders = append(r.RemoveHeaders, value)
+	default:
+		return fmt.Errorf("unknown option %q", key)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return nil
+}
\ No newline at end of file
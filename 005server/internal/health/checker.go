@@ -1 +1,216 @@
-package health
\ No newline at end of file
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusHealthy	Status = "healthy"
+	StatusUnhealthy	Status = "unhealthy"
+)
+
+// CheckFunc reports a component as unhealthy by returning an error. It must
+// honor ctx, which carries the check's timeout.
+type CheckFunc func(ctx context.Context) error
+
+type CheckResult struct {
+	Status		Status	`json:"status"`
+	Duration	string	`json:"duration"`
+	Error		string	`json:"error,omitempty"`
+}
+
+type Report struct {
+	Status		Status					`json:"status"`
+	Timestamp	string					`json:"timestamp"`
+	Uptime		string					`json:"uptime"`
+	Version		string					`json:"version"`
+	Checks		map[string]CheckResult	`json:"checks"`
+}
+
+func (r *Report) Healthy() bool {
+	return r.Status == StatusHealthy
+}
+
+type check struct {
+	name		string
+	timeout		time.Duration
+	fn			CheckFunc
+	readiness	bool
+}
+
+// Checker is a registry of named health checks. Liveness checks are reported
+// by both /health and /ready; readiness checks only by /ready.
+type Checker struct {
+	mu				sync.RWMutex
+	checks			[]check
+	version			string
+	defaultTimeout	time.Duration
+	started			time.Time
+	ready			atomic.Bool
+}
+
+func NewChecker(version string, defaultTimeout time.Duration) *Checker {
+	return &Checker{
+		version: version,
+		defaultTimeout: defaultTimeout,
+		started: time.Now(),
+	}
+}
+
+// Register adds a liveness check. A zero timeout uses the checker default.
+func (c *Checker) Register(name string, timeout time.Duration, fn CheckFunc) {
+	c.add(check{name: name, timeout: timeout, fn: fn})
+}
+
+// RegisterReadiness adds a check that only affects readiness.
+func (c *Checker) RegisterReadiness(name string, timeout time.Duration, fn CheckFunc) {
+	c.add(check{name: name, timeout: timeout, fn: fn, readiness: true})
+}
+
+// SetReady marks whether the server is accepting traffic.
+func (c *Checker) SetReady(ready bool) {
+	c.ready.Store(ready)
+}
+
+func (c *Checker) Uptime() time.Duration {
+	return time.Since(c.started)
+}
+
+// Health runs the liveness checks.
+func (c *Checker) Health(ctx context.Context) *Report {
+	return c.run(ctx, false)
+}
+
+// Ready runs every check and also fails while the server isn't accepting
+// traffic.
+func (c *Checker) Ready(ctx context.Context) *Report {
+	report := c.run(ctx, true)
+
+	result := CheckResult{Status: StatusHealthy, Duration: "0s"}
+	if !c.ready.Load() {
+		result.Status = StatusUnhealthy
+		result.Error = "server is not accepting traffic"
+		report.Status = StatusUnhealthy
+	}
+	report.Checks["server"] = result
+
+	return report
+}
+
+func (c *Checker) add(chk check) {
+	if chk.timeout <= 0 {
+		chk.timeout = c.defaultTimeout
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks = append(c.checks, chk)
+	sort.Slice(c.checks, func(i, j int) bool {
+		return c.checks[i].name < c.checks[j].name
+	})
+}
+
+func (c *Checker) run(ctx context.Context, readiness bool) *Report {
+	c.mu.RLock()
+	checks := make([]check, 0, len(c.checks))
+	for _, chk := range c.checks {
+		if readiness || !chk.readiness {
+			checks = append(checks, chk)
+		}
+	}
+	c.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, chk := range checks {
+		wg.Add(1)
+		go func(i int, chk check) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, chk)
+		}(i, chk)
+	}
+	wg.Wait()
+
+	report := &Report{
+		Status: StatusHealthy,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Uptime: c.Uptime().Round(time.Second).String(),
+		Version: c.version,
+		Checks: make(map[string]CheckResult, len(checks)),
+	}
+
+	for i, chk := range checks {
+		report.Checks[chk.name] = results[i]
+		if results[i].Status != StatusHealthy {
+			report.Status = StatusUnhealthy
+		}
+	}
+
+	return report
+}
+
+func runCheck(ctx context.Context, chk check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, chk.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errc := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				errc <- fmt.Errorf("check panicked: %v", p)
+			}
+		}()
+		errc <- chk.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		err = ctx.Err()
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", chk.timeout)
+		}
+	}
+
+	result := CheckResult{
+		Status: StatusHealthy,
+		Duration: time.Since(start).String(),
+	}
+	if err != nil {
+		result.Status = StatusUnhealthy
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// DirCheck fails when path is not a readable directory.
+func DirCheck(path string) CheckFunc {
+	return func(ctx context.Context) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", path)
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	}
+}
\ No newline at end of file
@@ -1 +1,199 @@
-package health
\ No newline at end of file
+package health
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBuckets are the latency histogram upper bounds in seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type requestKey struct {
+	route	string
+	method	string
+	status	int
+}
+
+type latencyKey struct {
+	route	string
+	status	int
+}
+
+type histogram struct {
+	counts	[]uint64
+	sum		float64
+	count	uint64
+}
+
+// Metrics collects HTTP request metrics and renders them, together with Go
+// runtime statistics, in the Prometheus text exposition format.
+type Metrics struct {
+	mu			sync.Mutex
+	buckets		[]float64
+	requests	map[requestKey]uint64
+	latencies	map[latencyKey]*histogram
+	inFlight	atomic.Int64
+	started		time.Time
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		buckets: DefaultBuckets,
+		requests: make(map[requestKey]uint64),
+		latencies: make(map[latencyKey]*histogram),
+		started: time.Now(),
+	}
+}
+
+func (m *Metrics) IncInFlight() {
+	m.inFlight.Add(1)
+}
+
+func (m *Metrics) DecInFlight() {
+	m.inFlight.Add(-1)
+}
+
+// Observe records a finished request.
+func (m *Metrics) Observe(route, method string, status int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[requestKey{route: route, method: method, status: status}]++
+
+	key := latencyKey{route: route, status: status}
+	h, ok := m.latencies[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latencies[key] = h
+	}
+
+	seconds := duration.Seconds()
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := m.Write(w); err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
+
+func (m *Metrics) Write(out io.Writer) error {
+	w := bufio.NewWriter(out)
+
+	m.writeHTTP(w)
+	m.writeRuntime(w)
+
+	return w.Flush()
+}
+
+func (m *Metrics) writeHTTP(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	header(w, "http_requests_total", "counter", "Total number of HTTP requests.")
+	reqKeys := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		reqKeys = append(reqKeys, k)
+	}
+	sort.Slice(reqKeys, func(i, j int) bool {
+		a, b := reqKeys[i], reqKeys[j]
+		if a.route != b.route {
+			return a.route < b.route
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.status < b.status
+	})
+	for _, k := range reqKeys {
+		fmt.Fprintf(w, "http_requests_total{route=%s,method=%s,status=\"%d\"} %d\n",
+			quote(k.route), quote(k.method), k.status, m.requests[k])
+	}
+
+	header(w, "http_request_duration_seconds", "histogram", "HTTP request latency in seconds.")
+	latKeys := make([]latencyKey, 0, len(m.latencies))
+	for k := range m.latencies {
+		latKeys = append(latKeys, k)
+	}
+	sort.Slice(latKeys, func(i, j int) bool {
+		a, b := latKeys[i], latKeys[j]
+		if a.route != b.route {
+			return a.route < b.route
+		}
+		return a.status < b.status
+	})
+	for _, k := range latKeys {
+		h := m.latencies[k]
+		labels := fmt.Sprintf("route=%s,status=\"%d\"", quote(k.route), k.status)
+		for i, bound := range m.buckets {
+			fmt.Fprintf(w, "http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels, formatFloat(bound), h.counts[i])
+		}
+		fmt.Fprintf(w, "http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.count)
+		fmt.Fprintf(w, "http_request_duration_seconds_sum{%s} %s\n", labels, formatFloat(h.sum))
+		fmt.Fprintf(w, "http_request_duration_seconds_count{%s} %d\n", labels, h.count)
+	}
+
+	header(w, "http_requests_in_flight", "gauge", "Number of HTTP requests currently being served.")
+	fmt.Fprintf(w, "http_requests_in_flight %d\n", m.inFlight.Load())
+}
+
+func (m *Metrics) writeRuntime(w io.Writer) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	header(w, "go_info", "gauge", "Information about the Go environment.")
+	fmt.Fprintf(w, "go_info{version=%s} 1\n", quote(runtime.Version()))
+
+	gauge(w, "go_goroutines", "Number of goroutines that currently exist.", float64(runtime.NumGoroutine()))
+	gauge(w, "go_gomaxprocs", "Value of GOMAXPROCS.", float64(runtime.GOMAXPROCS(0)))
+	gauge(w, "go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", float64(mem.Alloc))
+	counter(w, "go_memstats_alloc_bytes_total", "Total number of bytes allocated, even if freed.", float64(mem.TotalAlloc))
+	gauge(w, "go_memstats_sys_bytes", "Number of bytes obtained from the system.", float64(mem.Sys))
+	gauge(w, "go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use.", float64(mem.HeapInuse))
+	gauge(w, "go_memstats_heap_objects", "Number of allocated objects.", float64(mem.HeapObjects))
+	counter(w, "go_gc_cycles_total", "Number of completed GC cycles.", float64(mem.NumGC))
+	counter(w, "go_gc_pause_seconds_total", "Total GC stop-the-world pause time in seconds.", float64(mem.PauseTotalNs)/1e9)
+	gauge(w, "process_uptime_seconds", "Seconds since the server started.", time.Since(m.started).Seconds())
+}
+
+func header(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func gauge(w io.Writer, name, help string, value float64) {
+	header(w, name, "gauge", help)
+	fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+}
+
+func counter(w io.Writer, name, help string, value float64) {
+	header(w, name, "counter", help)
+	fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quote(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
\ No newline at end of file
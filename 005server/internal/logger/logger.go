@@ -8,22 +8,13 @@ import (
 
 type Logger struct {
 	*slog.Logger
+	// level is shared with the loggers derived from this one
+	level	*slog.LevelVar
 }
 
 func New(level, environment string) *Logger {
-	var logLevel slog.Level
-	switch strings.ToLower(level) {
-	case "debug":
-		logLevel = slog.LevelDebug
-	case "info":
-		logLevel = slog.LevelInfo
-	case "warn", "warning":
-		logLevel = slog.LevelWarn
-	case "error":
-		logLevel = slog.LevelError
-	default:
-		logLevel = slog.LevelInfo
-	}
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(parseLevel(level))
 
 	var handler slog.Handler
 	opts := &slog.HandlerOptions{
@@ -37,7 +28,30 @@ func New(level, environment string) *Logger {
 	}
 
 	logger := slog.New(handler)
-	return &Logger{Logger: logger}
+	return &Logger{Logger: logger, level: logLevel}
+}
+
+func parseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "info":
+		return slog.LevelInfo
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// SetLevel changes the minimum level of this logger and of every logger
+// derived from it.
+func (l *Logger) SetLevel(level string) {
+	if l.level != nil {
+		l.level.Set(parseLevel(level))
+	}
 }
 
 func (l *Logger) WithRequest(method, uri, userAgent string) *Logger {
@@ -47,11 +61,13 @@ func (l *Logger) WithRequest(method, uri, userAgent string) *Logger {
 			"uri", uri,
 			"user_agent", userAgent,
 		),
+		level: l.level,
 	}
 }
 
 func (l *Logger) WithError(err error) *Logger {
 	return &Logger{
 		Logger: l.Logger.With("error", err.Error()),
+		level: l.level,
 	}
 }
\ No newline at end of file
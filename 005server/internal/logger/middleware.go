@@ -1 +1,71 @@
-package logger
\ No newline at end of file
+package logger
+
+import (
+	"context"
+	"sync"
+)
+
+type contextKey struct{}
+
+type accessKey struct{}
+
+type accessAttrs struct {
+	mu		sync.Mutex
+	attrs	[]any
+}
+
+// NewContext returns a context carrying a request-scoped logger.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the request-scoped logger, or fallback when the context
+// doesn't carry one.
+func FromContext(ctx context.Context, fallback *Logger) *Logger {
+	if l, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return l
+	}
+	return fallback
+}
+
+// WithTrace tags every log line with the request and trace IDs.
+func (l *Logger) WithTrace(requestID, traceID string) *Logger {
+	return &Logger{
+		Logger: l.Logger.With(
+			"request_id", requestID,
+			"trace_id", traceID,
+		),
+		level: l.level,
+	}
+}
+
+// WithPrincipal tags every log line with the authenticated caller.
+func (l *Logger) WithPrincipal(subject, method string) *Logger {
+	return &Logger{
+		Logger: l.Logger.With(
+			"principal", subject,
+			"auth_method", method,
+		),
+		level: l.level,
+	}
+}
+
+// NewAccessContext returns a context that handlers further down the chain
+// can add access log attributes to, and a function returning them.
+func NewAccessContext(ctx context.Context) (context.Context, func() []any) {
+	a := &accessAttrs{}
+	return context.WithValue(ctx, accessKey{}, a), func() []any {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		return a.attrs
+	}
+}
+
+// AddAccessAttrs adds key-value pairs to the access log line of the request.
+func AddAccessAttrs(ctx context.Context, args ...any) {
+	if a, ok := ctx.Value(accessKey{}).(*accessAttrs); ok {
+		a.mu.Lock()
+		a.attrs = append(a.attrs, args...)
+		a.mu.Unlock()
+	}
+}
\ No newline at end of file
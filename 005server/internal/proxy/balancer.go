@@ -0,0 +1,58 @@
+package proxy
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Balancer picks the upstream for the next attempt, skipping unhealthy ones
+// and those already tried for this request.
+type Balancer interface {
+	Next(tried map[*Upstream]bool) *Upstream
+}
+
+func newBalancer(mode string, upstreams []*Upstream) (Balancer, error) {
+	switch mode {
+	case "", "round_robin":
+		return &roundRobin{upstreams: upstreams}, nil
+	case "least_conn":
+		return &leastConn{upstreams: upstreams}, nil
+	default:
+		return nil, fmt.Errorf("unknown balance mode: %s", mode)
+	}
+}
+
+type roundRobin struct {
+	upstreams	[]*Upstream
+	next		atomic.Uint64
+}
+
+func (rr *roundRobin) Next(tried map[*Upstream]bool) *Upstream {
+	n := len(rr.upstreams)
+	start := rr.next.Add(1) - 1
+
+	for i := 0; i < n; i++ {
+		up := rr.upstreams[(start+uint64(i))%uint64(n)]
+		if up.Healthy() && !tried[up] {
+			return up
+		}
+	}
+	return nil
+}
+
+type leastConn struct {
+	upstreams []*Upstream
+}
+
+func (lc *leastConn) Next(tried map[*Upstream]bool) *Upstream {
+	var best *Upstream
+	for _, up := range lc.upstreams {
+		if !up.Healthy() || tried[up] {
+			continue
+		}
+		if best == nil || up.ActiveRequests() < best.ActiveRequests() {
+			best = up
+		}
+	}
+	return best
+}
\ No newline at end of file
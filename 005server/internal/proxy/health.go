@@ -0,0 +1,82 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Start runs active health checks against every upstream until Close.
+func (rt *Route) Start() {
+	if rt.interval <= 0 || rt.healthPath == "" {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(rt.interval)
+		defer ticker.Stop()
+
+		rt.checkUpstreams()
+		for {
+			select {
+			case <-rt.done:
+				return
+			case <-ticker.C:
+				rt.checkUpstreams()
+			}
+		}
+	}()
+}
+
+// Check reports the route as unhealthy when none of its upstreams is in
+// rotation.
+func (rt *Route) Check(ctx context.Context) error {
+	for _, up := range rt.upstreams {
+		if up.Healthy() {
+			return nil
+		}
+	}
+	return fmt.Errorf("all %d upstreams are down", len(rt.upstreams))
+}
+
+func (rt *Route) checkUpstreams() {
+	timeout := rt.interval
+	if timeout > 5*time.Second {
+		timeout = 5 * time.Second
+	}
+
+	client := &http.Client{
+		Transport: rt.transport,
+		Timeout: timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	for _, up := range rt.upstreams {
+		target := *up.URL
+		target.Path = joinPath(target.Path, rt.healthPath)
+
+		ok := probe(client, target.String())
+		if !up.report(ok) {
+			continue
+		}
+
+		if ok {
+			rt.logger.Info("Upstream is back in rotation", "upstream", up.URL.Host)
+		} else {
+			rt.logger.Warn("Upstream taken out of rotation", "upstream", up.URL.Host)
+		}
+	}
+}
+
+func probe(client *http.Client, target string) bool {
+	resp, err := client.Get(target)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode < http.StatusBadRequest
+}
\ No newline at end of file
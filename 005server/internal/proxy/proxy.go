@@ -0,0 +1,299 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/samnart1/GoLang-Projects/005server/internal/config"
+	"github.com/samnart1/GoLang-Projects/005server/internal/logger"
+	"github.com/samnart1/GoLang-Projects/005server/internal/tracing"
+	"github.com/samnart1/GoLang-Projects/005server/pkg/response"
+)
+
+// maxReplayBody is the largest request body buffered so a request can be
+// retried on another upstream.
+const maxReplayBody = 1 << 20
+
+var errNoUpstream = errors.New("no healthy upstream available")
+
+type retriesKey struct{}
+
+// Route proxies one path prefix to a pool of upstreams.
+type Route struct {
+	cfg			config.ProxyRoute
+	upstreams	[]*Upstream
+	balancer	Balancer
+	proxy		*httputil.ReverseProxy
+	transport	http.RoundTripper
+	logger		*logger.Logger
+
+	healthPath	string
+	interval	time.Duration
+	done		chan struct{}
+}
+
+func NewRoute(cfg config.ProxyRoute, healthPath string, interval time.Duration, log *logger.Logger) (*Route, error) {
+	rt := &Route{
+		cfg: cfg,
+		logger: &logger.Logger{Logger: log.With("proxy", cfg.Prefix)},
+		healthPath: healthPath,
+		interval: interval,
+		done: make(chan struct{}),
+	}
+
+	if cfg.HealthPath != "" {
+		rt.healthPath = cfg.HealthPath
+	}
+
+	for _, target := range cfg.Targets {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid upstream %s: %w", target, err)
+		}
+		rt.upstreams = append(rt.upstreams, newUpstream(u))
+	}
+
+	balancer, err := newBalancer(cfg.Balance, rt.upstreams)
+	if err != nil {
+		return nil, err
+	}
+	rt.balancer = balancer
+
+	// the timeout bounds connecting and waiting for response headers only,
+	// so long downloads, event streams and upgraded connections stay open
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = cfg.Timeout
+	if cfg.Timeout > 0 {
+		transport.DialContext = (&net.Dialer{Timeout: cfg.Timeout, KeepAlive: 30 * time.Second}).DialContext
+	}
+	rt.transport = transport
+
+	rt.proxy = &httputil.ReverseProxy{
+		Rewrite: rt.rewrite,
+		Transport: roundTripperFunc(rt.roundTrip),
+		ModifyResponse: rt.modifyResponse,
+		ErrorHandler: rt.handleError,
+	}
+
+	return rt, nil
+}
+
+func (rt *Route) Prefix() string {
+	return rt.cfg.Prefix
+}
+
+func (rt *Route) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	retries := 0
+	if rt.cfg.Retries > 0 && idempotent(r.Method) && rt.makeReplayable(r) {
+		retries = rt.cfg.Retries
+	}
+
+	ctx := context.WithValue(r.Context(), retriesKey{}, retries)
+	rt.proxy.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// makeReplayable buffers small request bodies so they can be sent again. It
+// reports false if the body is too large to retry.
+func (rt *Route) makeReplayable(r *http.Request) bool {
+	if r.Body == nil || r.Body == http.NoBody {
+		return true
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxReplayBody+1))
+	if err != nil || len(body) > maxReplayBody {
+		// put back what was read so the request still goes through once
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+		return false
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return true
+}
+
+func (rt *Route) rewrite(pr *httputil.ProxyRequest) {
+	pr.SetXForwarded()
+
+	out := pr.Out
+	if rt.cfg.StripPrefix {
+		out.URL.Path = "/" + strings.TrimPrefix(out.URL.Path, rt.cfg.Prefix)
+		out.URL.RawPath = ""
+	}
+
+	for _, name := range rt.cfg.RemoveHeaders {
+		out.Header.Del(name)
+	}
+	for name, value := range rt.cfg.SetHeaders {
+		out.Header.Set(name, value)
+	}
+
+	if trace := tracing.FromContext(pr.In.Context()); trace != nil {
+		out.Header.Set(tracing.RequestIDHeader, trace.RequestID)
+		out.Header.Set(tracing.TraceparentHeader, trace.Traceparent())
+	}
+}
+
+func (rt *Route) modifyResponse(resp *http.Response) error {
+	for name, value := range rt.cfg.SetResponseHeaders {
+		resp.Header.Set(name, value)
+	}
+	return nil
+}
+
+func (rt *Route) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	log := logger.FromContext(r.Context(), rt.logger)
+
+	switch {
+	case errors.Is(err, errNoUpstream):
+		log.Warn("Proxy has no healthy upstream", "prefix", rt.cfg.Prefix)
+		response.Error(w, http.StatusServiceUnavailable, "No upstream available")
+	case isTimeout(err):
+		log.Warn("Proxy upstream timed out", "prefix", rt.cfg.Prefix, "error", err)
+		response.Error(w, http.StatusGatewayTimeout, "Upstream timed out")
+	case errors.Is(err, context.Canceled):
+		// the client went away, nobody is left to answer
+	default:
+		log.Error("Proxy upstream failed", "prefix", rt.cfg.Prefix, "error", err)
+		response.Error(w, http.StatusBadGateway, "Bad gateway")
+	}
+}
+
+// isTimeout reports whether err is the upstream failing to connect or answer
+// in time.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// roundTrip sends the request to an upstream picked by the balancer, moving on
+// to the next one on connection errors and 502/503/504 while retries remain.
+func (rt *Route) roundTrip(req *http.Request) (*http.Response, error) {
+	retries, _ := req.Context().Value(retriesKey{}).(int)
+	tried := make(map[*Upstream]bool)
+
+	var resp *http.Response
+	var err error
+	var up *Upstream
+
+	for attempt := 0; attempt <= retries; attempt++ {
+		next := rt.balancer.Next(tried)
+		if next == nil {
+			break
+		}
+
+		if resp != nil {
+			// a retryable response is only dropped once there is another
+			// upstream to try
+			discard(resp, up)
+			resp = nil
+		}
+
+		up = next
+		tried[up] = true
+
+		out := req.Clone(req.Context())
+		if attempt > 0 && req.GetBody != nil {
+			if out.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		out.URL.Scheme = up.URL.Scheme
+		out.URL.Host = up.URL.Host
+		out.URL.Path = joinPath(up.URL.Path, out.URL.Path)
+		out.URL.RawPath = ""
+		out.Host = ""
+
+		up.active.Add(1)
+		resp, err = rt.transport.RoundTrip(out)
+		if err != nil {
+			up.active.Add(-1)
+			resp = nil
+			if req.Context().Err() != nil {
+				return nil, req.Context().Err()
+			}
+			rt.logger.Debug("Upstream attempt failed", "upstream", up.URL.Host, "attempt", attempt+1, "error", err)
+			continue
+		}
+
+		if !retryableStatus(resp.StatusCode) {
+			break
+		}
+		rt.logger.Debug("Upstream attempt failed", "upstream", up.URL.Host, "attempt", attempt+1, "status", resp.StatusCode)
+	}
+
+	if resp == nil {
+		if err == nil {
+			err = errNoUpstream
+		}
+		return nil, err
+	}
+
+	resp.Body = &trackedBody{ReadCloser: resp.Body, upstream: up}
+	return resp, nil
+}
+
+func (rt *Route) Close() {
+	select {
+	case <-rt.done:
+	default:
+		close(rt.done)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// trackedBody keeps the upstream's active request count up to date until the
+// response has been fully copied to the client.
+type trackedBody struct {
+	io.ReadCloser
+	upstream	*Upstream
+	closed		bool
+}
+
+func (b *trackedBody) Close() error {
+	if !b.closed {
+		b.closed = true
+		b.upstream.active.Add(-1)
+	}
+	return b.ReadCloser.Close()
+}
+
+func discard(resp *http.Response, up *Upstream) {
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	up.active.Add(-1)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusBadGateway || code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+func joinPath(base, path string) string {
+	if base == "" || base == "/" {
+		return path
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+}
\ No newline at end of file
@@ -0,0 +1,64 @@
+package proxy
+
+import (
+	"net/url"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// consecutive failed checks before an upstream is taken out of rotation
+	fallThreshold = 2
+	// consecutive passed checks before it is put back
+	riseThreshold = 1
+)
+
+type Upstream struct {
+	URL		*url.URL
+	active	atomic.Int64
+	healthy	atomic.Bool
+
+	mu		sync.Mutex
+	fails	int
+	passes	int
+}
+
+func newUpstream(u *url.URL) *Upstream {
+	up := &Upstream{URL: u}
+	up.healthy.Store(true)
+	return up
+}
+
+func (u *Upstream) Healthy() bool {
+	return u.healthy.Load()
+}
+
+// ActiveRequests is the number of requests currently forwarded to u.
+func (u *Upstream) ActiveRequests() int64 {
+	return u.active.Load()
+}
+
+// report records a health check result and returns true if it changed the
+// upstream's state.
+func (u *Upstream) report(ok bool) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if ok {
+		u.fails = 0
+		u.passes++
+		if !u.healthy.Load() && u.passes >= riseThreshold {
+			u.healthy.Store(true)
+			return true
+		}
+		return false
+	}
+
+	u.passes = 0
+	u.fails++
+	if u.healthy.Load() && u.fails >= fallThreshold {
+		u.healthy.Store(false)
+		return true
+	}
+	return false
+}
\ No newline at end of file
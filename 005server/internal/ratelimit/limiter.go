@@ -0,0 +1,141 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Decision is the outcome of taking a token from a client's bucket.
+type Decision struct {
+	Allowed		bool
+	// Limit is the burst, the most requests a full bucket allows at once
+	Limit		int
+	Remaining	int
+	// RetryAfter is how long until the next token is available.
+	RetryAfter	time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset		time.Duration
+}
+
+type bucket struct {
+	tokens		float64
+	updated		time.Time
+}
+
+// Limiter is a set of token buckets keyed by client. Each bucket refills at
+// rate tokens per second up to burst, and buckets that have been idle for
+// longer than idle are evicted in the background.
+type Limiter struct {
+	mu		sync.Mutex
+	rate	float64
+	burst	int
+	idle	time.Duration
+	buckets	map[string]*bucket
+	done	chan struct{}
+	now		func() time.Time
+}
+
+func New(rps, burst int, idle time.Duration) *Limiter {
+	if burst <= 0 {
+		burst = rps
+	}
+
+	l := &Limiter{
+		rate: float64(rps),
+		burst: burst,
+		idle: idle,
+		buckets: make(map[string]*bucket),
+		done: make(chan struct{}),
+		now: time.Now,
+	}
+
+	if idle > 0 {
+		go l.evictLoop()
+	}
+
+	return l
+}
+
+// Allow takes a token from key's bucket if one is available.
+func (l *Limiter) Allow(key string) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), updated: now}
+		l.buckets[key] = b
+	} else {
+		b.tokens = math.Min(float64(l.burst), b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+		b.updated = now
+	}
+
+	d := Decision{Limit: l.burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = l.wait(1 - b.tokens)
+	}
+
+	d.Remaining = int(b.tokens)
+	d.Reset = l.wait(float64(l.burst) - b.tokens)
+
+	return d
+}
+
+// Len returns the number of tracked clients.
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.buckets)
+}
+
+// Stop ends background eviction.
+func (l *Limiter) Stop() {
+	select {
+	case <-l.done:
+	default:
+		close(l.done)
+	}
+}
+
+func (l *Limiter) wait(tokens float64) time.Duration {
+	if tokens <= 0 || l.rate <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
+
+// minEvictInterval keeps very short idle timeouts from spinning the
+// eviction loop.
+const minEvictInterval = time.Second
+
+func (l *Limiter) evictLoop() {
+	ticker := time.NewTicker(max(l.idle/2, minEvictInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
+			l.evict()
+		}
+	}
+}
+
+func (l *Limiter) evict() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := l.now().Add(-l.idle)
+	for key, b := range l.buckets {
+		if b.updated.Before(cutoff) {
+			delete(l.buckets, key)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// trustedProxies decides whether X-Forwarded-For can be believed for a
+// request, based on the address of the peer that sent it.
+type trustedProxies struct {
+	nets []*net.IPNet
+}
+
+func newTrustedProxies(entries []string) (*trustedProxies, error) {
+	tp := &trustedProxies{}
+
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			if strings.Contains(entry, ":") {
+				entry += "/128"
+			} else {
+				entry += "/32"
+			}
+		}
+
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, err
+		}
+		tp.nets = append(tp.nets, ipNet)
+	}
+
+	return tp, nil
+}
+
+func (tp *trustedProxies) contains(ip net.IP) bool {
+	for _, n := range tp.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// fromProxy reports whether the direct peer is a trusted proxy.
+func (tp *trustedProxies) fromProxy(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	peer := net.ParseIP(host)
+	return peer != nil && tp.contains(peer)
+}
+
+// clientIP returns the address of the client that made the request. When the
+// direct peer is a trusted proxy, X-Forwarded-For is walked from the right and
+// the first address that isn't a trusted proxy is used.
+func (tp *trustedProxies) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	peer := net.ParseIP(host)
+	if peer == nil || !tp.contains(peer) {
+		return host
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		ip := net.ParseIP(hop)
+		if ip == nil {
+			break
+		}
+		if !tp.contains(ip) {
+			return ip.String()
+		}
+		host = ip.String()
+	}
+
+	return host
+}
\ No newline at end of file
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/samnart1/GoLang-Projects/005server/internal/compress"
+)
+
+// maxETagBody is the largest response buffered to compute an ETag. Larger
+// ones are sent without one.
+const maxETagBody = 1 << 20
+
+// etagTypes are the generated responses that get an ETag; static files and
+// proxied responses bring their own validators.
+var etagTypes = compress.Types{"application/json", "*+json", "text/html"}
+
+// conditionalMiddleware adds an ETag to successful JSON and HTML responses
+// and answers If-None-Match and If-Modified-Since with 304 Not Modified. It
+// sits outside the compression middleware, so each encoding gets its own
+// strong ETag.
+func (s *Server) conditionalMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &conditionalWriter{ResponseWriter: w}
+		next.ServeHTTP(cw, r)
+		cw.finish(r)
+	})
+}
+
+type conditionalWriter struct {
+	http.ResponseWriter
+	status		int
+	buf			bytes.Buffer
+	passthrough	bool
+}
+
+func (w *conditionalWriter) WriteHeader(code int) {
+	if w.status != 0 {
+		return
+	}
+	if code >= 100 && code < 200 {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+
+	header := w.Header()
+	if code != http.StatusOK || header.Get("ETag") != "" || !etagTypes.Match(header.Get("Content-Type")) {
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *conditionalWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.buf.Write(p)
+	if w.buf.Len() > maxETagBody {
+		w.release()
+	}
+	return len(p), nil
+}
+
+// release sends what has been held back and stops buffering.
+func (w *conditionalWriter) release() {
+	w.passthrough = true
+	w.ResponseWriter.WriteHeader(w.status)
+	w.buf.WriteTo(w.ResponseWriter)
+}
+
+// Flush gives up on the ETag, since a flushing handler is streaming.
+func (w *conditionalWriter) Flush() {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.passthrough {
+		w.release()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *conditionalWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *conditionalWriter) finish(r *http.Request) {
+	if w.status == 0 || w.passthrough {
+		return
+	}
+
+	sum := sha256.Sum256(w.buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	header := w.Header()
+	header.Set("ETag", etag)
+
+	if notModified(r, etag, header.Get("Last-Modified")) {
+		header.Del("Content-Type")
+		header.Del("Content-Length")
+		header.Del("Content-Encoding")
+		w.ResponseWriter.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	w.buf.WriteTo(w.ResponseWriter)
+}
+
+// notModified evaluates the preconditions in the order RFC 9110 gives:
+// If-Modified-Since only counts when there is no If-None-Match.
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			// weak comparison, as for GET and HEAD
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified == "" {
+		return false
+	}
+
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
\ No newline at end of file
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/samnart1/GoLang-Projects/005server/internal/config"
+)
+
+// corsPolicy is a config.CORSPolicy with its header values worked out once.
+type corsPolicy struct {
+	enabled		bool
+	anyOrigin	bool
+	origins		map[string]bool
+	// wildcards are origins like https://*.example.com split around the *
+	wildcards	[][2]string
+	methods		map[string]bool
+	allowMethods	string
+	allowHeaders	string
+	exposeHeaders	string
+	credentials	bool
+	maxAge		string
+}
+
+type corsRoute struct {
+	prefix	string
+	policy	*corsPolicy
+}
+
+// corsPolicies holds the default policy and the per-route overrides.
+type corsPolicies struct {
+	fallback	*corsPolicy
+	routes		[]corsRoute
+}
+
+func newCORSPolicies(cfg *config.Config) *corsPolicies {
+	cp := &corsPolicies{fallback: newCORSPolicy(cfg.CORS)}
+	for _, route := range cfg.CORSRoutes {
+		cp.routes = append(cp.routes, corsRoute{prefix: route.Prefix, policy: newCORSPolicy(route.Policy)})
+	}
+
+	// longest prefix wins
+	sort.SliceStable(cp.routes, func(i, j int) bool {
+		return len(cp.routes[i].prefix) > len(cp.routes[j].prefix)
+	})
+
+	return cp
+}
+
+func (cp *corsPolicies) forPath(path string) *corsPolicy {
+	for _, route := range cp.routes {
+		if strings.HasPrefix(path, route.prefix) {
+			return route.policy
+		}
+	}
+	return cp.fallback
+}
+
+func newCORSPolicy(cfg config.CORSPolicy) *corsPolicy {
+	p := &corsPolicy{
+		enabled: cfg.Enabled,
+		origins: make(map[string]bool),
+		methods: make(map[string]bool),
+		allowMethods: strings.Join(cfg.AllowedMethods, ", "),
+		allowHeaders: strings.Join(cfg.AllowedHeaders, ", "),
+		exposeHeaders: strings.Join(cfg.ExposedHeaders, ", "),
+		credentials: cfg.AllowCredentials,
+		maxAge: strconv.Itoa(int(cfg.MaxAge.Seconds())),
+	}
+
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+		switch {
+		case origin == "*":
+			p.anyOrigin = true
+		case strings.Contains(origin, "*"):
+			before, after, _ := strings.Cut(origin, "*")
+			p.wildcards = append(p.wildcards, [2]string{before, after})
+		default:
+			p.origins[origin] = true
+		}
+	}
+
+	for _, method := range cfg.AllowedMethods {
+		p.methods[strings.ToUpper(method)] = true
+	}
+
+	return p
+}
+
+func (p *corsPolicy) allowOrigin(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if p.origins[origin] {
+		return true
+	}
+
+	for _, w := range p.wildcards {
+		if len(origin) <= len(w[0])+len(w[1]) || !strings.HasPrefix(origin, w[0]) || !strings.HasSuffix(origin, w[1]) {
+			continue
+		}
+		// the * only stands in for subdomain labels
+		sub := origin[len(w[0]) : len(origin)-len(w[1])]
+		if !strings.ContainsAny(sub, "/:@") {
+			return true
+		}
+	}
+	return false
+}
+
+// varies reports whether the response depends on the Origin header, which
+// is the case unless every origin gets the same "*".
+func (p *corsPolicy) varies() bool {
+	return !p.anyOrigin || p.credentials
+}
+
+func (s *Server) corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// policies are swapped out when the configuration is reloaded
+		policy := s.cors.Load().forPath(r.URL.Path)
+		if !policy.enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		if policy.varies() {
+			w.Header().Add("Vary", "Origin")
+		}
+		if preflight {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		origin := r.Header.Get("Origin")
+		allowed := origin != "" && policy.allowOrigin(origin)
+
+		if preflight {
+			// a refused preflight gets no CORS headers, so the browser
+			// blocks the real request
+			if allowed && policy.methods[strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))] {
+				policy.writeOrigin(w, origin)
+				w.Header().Set("Access-Control-Allow-Methods", policy.allowMethods)
+				if policy.allowHeaders != "" {
+					w.Header().Set("Access-Control-Allow-Headers", policy.allowHeaders)
+				}
+				w.Header().Set("Access-Control-Max-Age", policy.maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if allowed {
+			policy.writeOrigin(w, origin)
+			if policy.exposeHeaders != "" {
+				w.Header().Set("Access-Control-Expose-Headers", policy.exposeHeaders)
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (p *corsPolicy) writeOrigin(w http.ResponseWriter, origin string) {
+	if p.anyOrigin && !p.credentials {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	if p.credentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
\ No newline at end of file
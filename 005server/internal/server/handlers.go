@@ -1,18 +1,22 @@
 package server
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"path/filepath"
 	"runtime"
-	"text/template"
 	"time"
 
+	"github.com/samnart1/GoLang-Projects/005server/internal/auth"
+	"github.com/samnart1/GoLang-Projects/005server/internal/health"
+	"github.com/samnart1/GoLang-Projects/005server/internal/logger"
+	"github.com/samnart1/GoLang-Projects/005server/internal/tracing"
 	"github.com/samnart1/GoLang-Projects/005server/pkg/response"
 )
 
 type PageData struct {
+	Active		string
 	Title		string
 	Message		string
 	CurrentTime	string
@@ -26,13 +30,14 @@ func (s *Server) handleHome(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := PageData{
+		Active: 		"home",
 		Title: 			"Go HTTP Server",
 		Message: 		"Welcome to the Go HTTP Server!",
 		CurrentTime: 	time.Now().Format(time.RFC3339),
 		Version: 		"1.0.0",
 	}
 
-	s.renderTemplate(w, "index.html", data)
+	s.renderTemplate(w, r, http.StatusOK, "index.html", data)
 }
 
 func (s *Server) handleHello(w http.ResponseWriter, r *http.Request) {
@@ -57,12 +62,13 @@ func (s *Server) handleHello(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleAbout(w http.ResponseWriter, r *http.Request) {
 	data := PageData{
+		Active:	"about",
 		Title:	"About - Go Http Server",
 		Message: "This is a simple http server built with Go to demonstrate web development concepts",
 		Version: "1.0.0",
 	}
 
-	s.renderTemplate(w, "about.html", data)
+	s.renderTemplate(w, r, http.StatusOK, "about.html", data)
 }
 
 func (s *Server) handleAPIInfo(w http.ResponseWriter, r *http.Request) {
@@ -119,6 +125,10 @@ func (s *Server) handleAPIEcho(w http.ResponseWriter, r *http.Request) {
 		echo["body"] = body
 	}
 
+	if principal := auth.FromContext(r.Context()); principal != nil {
+		echo["principal"] = principal
+	}
+
 	response.JSON(w, http.StatusOK, echo)
 }
 
@@ -142,23 +152,28 @@ func (s *Server) handleAPITime(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	health := map[string]interface{}{
-		"status":		"healthy",
-		"timestamp":	time.Now().Format(time.RFC3339),
-		"uptime":		time.Since(time.Now()).String(),
-		"version":		"1.0.0",
-	}
+	end := tracing.StartSpan(r.Context(), "health.checks")
+	report := s.checker.Health(r.Context())
+	end()
 
-	response.JSON(w, http.StatusOK, health)
+	s.writeReport(w, report)
 }
 
 func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
-	ready := map[string]interface{}{
-		"ready":	true,
-		"timestamp":	time.Now().Format(time.RFC3339),
+	end := tracing.StartSpan(r.Context(), "health.checks")
+	report := s.checker.Ready(r.Context())
+	end()
+
+	s.writeReport(w, report)
+}
+
+func (s *Server) writeReport(w http.ResponseWriter, report *health.Report) {
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
 	}
 
-	response.JSON(w, http.StatusOK, ready)
+	response.JSON(w, status, report)
 }
 
 func (s *Server) handleNotFound(w http.ResponseWriter, r *http.Request) {
@@ -172,25 +187,25 @@ func (s *Server) handleNotFound(w http.ResponseWriter, r *http.Request) {
 		Message: fmt.Sprintf("The page '%s' was not found!", r.URL.Path),
 	}
 
-	w.WriteHeader(http.StatusNotFound)
-	s.renderTemplate(w, "error.html", data)
+	s.renderTemplate(w, r, http.StatusNotFound, "error.html", data)
 }
 
-func (s *Server) renderTemplate(w http.ResponseWriter, templateName string, data PageData) {
-	templatePath := filepath.Join(s.config.TemplateDir, templateName)
+func (s *Server) renderTemplate(w http.ResponseWriter, r *http.Request, status int, templateName string, data PageData) {
+	end := tracing.StartSpan(r.Context(), "template")
+	defer end()
 
-	tmpl, err := template.ParseFiles(templatePath)
-	if err != nil {
-		s.logger.Error("Template parse error", "template", templateName, "error", err)
+	var buf bytes.Buffer
+	if err := s.templates.Render(&buf, templateName, data); err != nil {
+		logger.FromContext(r.Context(), s.logger).Error("Template execution error", "template", templateName, "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-
-	if err := tmpl.Execute(w, data); err != nil {
-		s.logger.Error("Template execution error", "template", templateName, "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
+	// pages without a timestamp only change when the templates do
+	if data.CurrentTime == "" && status == http.StatusOK {
+		w.Header().Set("Last-Modified", s.templates.ModTime().UTC().Format(http.TimeFormat))
 	}
+	w.WriteHeader(status)
+	buf.WriteTo(w)
 }
\ No newline at end of file
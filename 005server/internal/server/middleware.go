@@ -1,14 +1,43 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 	"time"
+
+	"github.com/samnart1/GoLang-Projects/005server/internal/auth"
+	"github.com/samnart1/GoLang-Projects/005server/internal/compress"
+	"github.com/samnart1/GoLang-Projects/005server/internal/logger"
+	"github.com/samnart1/GoLang-Projects/005server/internal/tracing"
+	"github.com/samnart1/GoLang-Projects/005server/pkg/response"
 )
 
+func (s *Server) requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// spans are only worth recording when someone will see them
+		trace := tracing.FromRequest(r, s.logger.Enabled(r.Context(), slog.LevelDebug))
+
+		w.Header().Set(tracing.RequestIDHeader, trace.RequestID)
+		w.Header().Set(tracing.TraceparentHeader, trace.Traceparent())
+
+		reqLogger := s.logger.WithTrace(trace.RequestID, trace.TraceID)
+
+		ctx := tracing.NewContext(r.Context(), trace)
+		ctx = logger.NewContext(ctx, reqLogger)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+		log := logger.FromContext(r.Context(), s.logger).WithRequest(r.Method, r.RequestURI, r.UserAgent())
 
 		// Create a response writer wrapper to capture status code
 		wrapped := &responseWriter{
@@ -16,31 +45,190 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 			statusCode:     http.StatusOK,
 		}
 
+		// inner middleware such as auth adds to the access log line
+		ctx, accessAttrs := logger.NewAccessContext(r.Context())
+
 		// Call the next handler
-		next.ServeHTTP(wrapped, r)
+		next.ServeHTTP(wrapped, r.WithContext(ctx))
 
 		// Log the request
 		duration := time.Since(start)
-		s.logger.Info("HTTP request",
-			"method", r.Method,
-			"uri", r.RequestURI,
+		attrs := []any{
 			"status", wrapped.statusCode,
 			"duration", duration.String(),
-			"user_agent", r.UserAgent(),
 			"remote_addr", r.RemoteAddr,
-		)
+		}
+		log.Info("HTTP request", append(attrs, accessAttrs()...)...)
+
+		if trace := tracing.FromContext(r.Context()); trace != nil {
+			logTiming(log, trace, duration)
+		}
+	})
+}
+
+// logTiming writes the span breakdown of a request at debug level.
+func logTiming(log *logger.Logger, trace *tracing.Trace, total time.Duration) {
+	spans := trace.Spans()
+	if len(spans) == 0 {
+		return
+	}
+
+	attrs := make([]any, 0, len(spans))
+	for _, span := range spans {
+		attrs = append(attrs, slog.Group(span.Name,
+			"start", span.Start.String(),
+			"duration", span.Duration.String(),
+		))
+	}
+
+	log.Debug("Request timing",
+		"total", total.String(),
+		slog.Group("spans", attrs...),
+	)
+}
+
+// spanMiddleware records the time spent in next as a span named name.
+func (s *Server) spanMiddleware(name string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		end := tracing.StartSpan(r.Context(), name)
+		defer end()
+
+		next.ServeHTTP(w, r)
 	})
 }
 
-func (s *Server) corsMiddleware(next http.Handler) http.Handler {
+func (s *Server) metricsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
-		w.Header().Set("Access-Control-Max-Age", "86400")
+		start := time.Now()
+		s.metrics.IncInFlight()
+		defer s.metrics.DecInFlight()
+
+		wrapped := &responseWriter{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+
+		// inner middleware hands a copy of the request to the mux, so the
+		// matched pattern is passed back through the context
+		var route string
+		next.ServeHTTP(wrapped, r.WithContext(context.WithValue(r.Context(), routeKey{}, &route)))
+
+		if route == "" {
+			route = "unmatched"
+		}
 
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusOK)
+		s.metrics.Observe(route, r.Method, wrapped.statusCode, time.Since(start))
+	})
+}
+
+// routeKey carries the *string metricsMiddleware reads the route label from.
+type routeKey struct{}
+
+// routeMiddleware sits directly around the mux and reports the pattern it
+// matched, which keeps the route label bounded.
+func (s *Server) routeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// deferred so requests that panic are still labelled
+		defer func() {
+			if route, ok := r.Context().Value(routeKey{}).(*string); ok {
+				*route = r.Pattern
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) compressionMiddleware(next http.Handler) http.Handler {
+	return compress.Handler(next, compress.Options{
+		MinSize: s.config.CompressionMinSize,
+		Types: compress.Types(s.config.CompressionTypes),
+		Encodings: s.config.CompressionEncodings,
+	})
+}
+
+func (s *Server) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		guard := s.auth.Load()
+		if guard == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// preflight requests are answered by the cors middleware, which
+		// runs before this one
+		route := guard.Match(r.URL.Path)
+		if route == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		end := tracing.StartSpan(r.Context(), "auth")
+		principal, err := route.Authenticate(r)
+		end()
+
+		ctx := r.Context()
+		if principal != nil {
+			logger.AddAccessAttrs(ctx, "principal", principal.Subject, "auth_method", principal.Method)
+		}
+
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Error(w, http.StatusForbidden, err.Error())
+			return
+		case err != nil:
+			for _, challenge := range route.Challenges() {
+				w.Header().Add("WWW-Authenticate", challenge)
+			}
+			logger.AddAccessAttrs(ctx, "auth_error", err.Error())
+			response.Error(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		ctx = auth.NewContext(ctx, principal)
+		ctx = logger.NewContext(ctx, logger.FromContext(ctx, s.logger).WithPrincipal(principal.Subject, principal.Method))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the limits are swapped out when the configuration is reloaded
+		limits := s.rateLimits.Load()
+		if limits == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limiter := limits.forPath(r.URL.Path)
+		if limiter == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// the header is set by a proxy in front of the server; clients
+		// talking to it directly could pick a fresh value every request
+		key := s.proxies.clientIP(r)
+		if limits.header != "" && s.proxies.fromProxy(r) {
+			if value := r.Header.Get(limits.header); value != "" {
+				key = limits.header + ":" + value
+			}
+		}
+
+		end := tracing.StartSpan(r.Context(), "ratelimit")
+		decision := limiter.Allow(key)
+		end()
+
+		// the limit is the bucket size, the most requests a client can
+		// make at once, while the rate refills it
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+
+		if !decision.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+			response.Error(w, http.StatusTooManyRequests, "Rate limit exceeded")
 			return
 		}
 
@@ -48,11 +236,15 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
 func (s *Server) recoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				s.logger.Error("Panic recovered",
+				logger.FromContext(r.Context(), s.logger).Error("Panic recovered",
 					"error", err,
 					"method", r.Method,
 					"uri", r.RequestURI,
@@ -75,4 +267,10 @@ type responseWriter struct {
 func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the connection's writer, so
+// flushes and hijacks pass through the logging and metrics wrappers.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
 }
\ No newline at end of file
@@ -0,0 +1,235 @@
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samnart1/GoLang-Projects/005server/internal/config"
+	"github.com/samnart1/GoLang-Projects/005server/internal/health"
+	"github.com/samnart1/GoLang-Projects/005server/internal/logger"
+)
+
+// newTestServer builds a Server with just enough state to run the
+// middleware chain.
+func newTestServer(cfg *config.Config) *Server {
+	s := &Server{
+		config: cfg,
+		logger: logger.New("error", "test"),
+	}
+	if cfg.EnableMetrics {
+		s.metrics = health.NewMetrics()
+	}
+	s.cors.Store(newCORSPolicies(cfg))
+	s.security.Store(newSecurityHeaders(cfg.Security))
+	return s
+}
+
+func TestMetricsMiddleware_RouteLabel(t *testing.T) {
+	s := newTestServer(&config.Config{EnableMetrics: true})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/time", s.handleAPITime)
+	handler := s.applyMiddleware(mux)
+
+	for _, path := range []string{"/api/time", "/missing"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	var out strings.Builder
+	if err := s.metrics.Write(&out); err != nil {
+		t.Fatalf("Failed to write metrics: %v", err)
+	}
+
+	expected := []string{
+		`http_requests_total{route="/api/time",method="GET",status="200"} 1`,
+		`http_requests_total{route="unmatched",method="GET",status="404"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestMiddleware_FlushReachesClient(t *testing.T) {
+	s := newTestServer(&config.Config{EnableMetrics: true})
+
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stream", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "first\n")
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Flush failed: %v", err)
+		}
+		<-release
+	})
+
+	srv := httptest.NewServer(s.applyMiddleware(mux))
+	defer srv.Close()
+	defer close(release)
+
+	// without a flush the headers only arrive once the handler returns, so
+	// even the request itself would block
+	line := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(srv.URL + "/stream")
+		if err != nil {
+			line <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+
+		text, _ := bufio.NewReader(resp.Body).ReadString('\n')
+		line <- text
+	}()
+
+	select {
+	case text := <-line:
+		if text != "first\n" {
+			t.Errorf("Expected first line, got %q", text)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Flushed bytes did not arrive before the handler returned")
+	}
+}
+
+func TestMiddleware_CompressedFlushAndHijack(t *testing.T) {
+	s := newTestServer(&config.Config{
+		EnableMetrics:        true,
+		EnableCompression:    true,
+		EnableETags:          true,
+		CompressionMinSize:   1024,
+		CompressionTypes:     []string{"text/plain"},
+		CompressionEncodings: []string{"gzip"},
+	})
+
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "first\n")
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Flush failed: %v", err)
+		}
+		<-release
+	})
+	mux.HandleFunc("/upgrade", func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Errorf("Hijack failed: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer conn.Close()
+
+		buf.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\nhello\n")
+		buf.Flush()
+	})
+
+	srv := httptest.NewServer(s.applyMiddleware(mux))
+	defer srv.Close()
+	defer close(release)
+
+	t.Run("flush", func(t *testing.T) {
+		line := make(chan string, 1)
+		go func() {
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+"/stream", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				line <- err.Error()
+				return
+			}
+			defer resp.Body.Close()
+
+			zr, err := gzip.NewReader(resp.Body)
+			if err != nil {
+				line <- err.Error()
+				return
+			}
+			text, _ := bufio.NewReader(zr).ReadString('\n')
+			line <- text
+		}()
+
+		select {
+		case text := <-line:
+			if text != "first\n" {
+				t.Errorf("Expected first line, got %q", text)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("Flushed bytes did not arrive before the handler returned")
+		}
+	})
+
+	t.Run("hijack", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/upgrade", nil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "test")
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusSwitchingProtocols {
+			t.Fatalf("Expected 101, got %d", resp.StatusCode)
+		}
+		text, _ := bufio.NewReader(resp.Body).ReadString('\n')
+		if text != "hello\n" {
+			t.Errorf("Expected hello over the hijacked connection, got %q", text)
+		}
+	})
+}
+
+func TestRateLimitMiddleware_HeaderNeedsTrustedProxy(t *testing.T) {
+	cfg := &config.Config{
+		EnableRateLimit: true,
+		RateLimitRPS:    1,
+		RateLimitBurst:  1,
+		RateLimitHeader: "X-Client",
+		TrustedProxies:  []string{"10.0.0.1"},
+	}
+	s := newTestServer(cfg)
+	s.proxies, _ = newTrustedProxies(cfg.TrustedProxies)
+	s.rateLimits.Store(newRateLimits(cfg))
+	defer s.rateLimits.Load().stop()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {})
+	handler := s.applyMiddleware(mux)
+
+	tests := []struct {
+		name     string
+		remote   string
+		expected []int
+	}{
+		{"direct client", "192.0.2.1:1234", []int{http.StatusOK, http.StatusTooManyRequests}},
+		{"trusted proxy", "10.0.0.1:1234", []int{http.StatusOK, http.StatusOK}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, status := range tt.expected {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.RemoteAddr = tt.remote
+				req.Header.Set("X-Client", fmt.Sprintf("client-%d", i))
+
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				if rec.Code != status {
+					t.Errorf("Request %d: expected %d, got %d", i, status, rec.Code)
+				}
+			}
+		})
+	}
+}
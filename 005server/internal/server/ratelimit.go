@@ -0,0 +1,64 @@
+package server
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/samnart1/GoLang-Projects/005server/internal/config"
+	"github.com/samnart1/GoLang-Projects/005server/internal/ratelimit"
+)
+
+type routeLimiter struct {
+	prefix	string
+	// limiter is nil for routes exempt from rate limiting
+	limiter	*ratelimit.Limiter
+}
+
+// rateLimits holds the default limiter and the per-route overrides, each with
+// its own set of client buckets.
+type rateLimits struct {
+	fallback	*ratelimit.Limiter
+	routes		[]routeLimiter
+	// header, when set, keys clients by this request header instead of IP
+	header		string
+}
+
+func newRateLimits(cfg *config.Config) *rateLimits {
+	rl := &rateLimits{
+		fallback: ratelimit.New(cfg.RateLimitRPS, cfg.RateLimitBurst, cfg.RateLimitIdle),
+		header: cfg.RateLimitHeader,
+	}
+
+	for _, route := range cfg.RateLimitRoutes {
+		rlr := routeLimiter{prefix: route.Prefix}
+		if route.RPS > 0 {
+			rlr.limiter = ratelimit.New(route.RPS, route.Burst, cfg.RateLimitIdle)
+		}
+		rl.routes = append(rl.routes, rlr)
+	}
+
+	// longest prefix wins
+	sort.SliceStable(rl.routes, func(i, j int) bool {
+		return len(rl.routes[i].prefix) > len(rl.routes[j].prefix)
+	})
+
+	return rl
+}
+
+func (rl *rateLimits) forPath(path string) *ratelimit.Limiter {
+	for _, route := range rl.routes {
+		if strings.HasPrefix(path, route.prefix) {
+			return route.limiter
+		}
+	}
+	return rl.fallback
+}
+
+func (rl *rateLimits) stop() {
+	rl.fallback.Stop()
+	for _, route := range rl.routes {
+		if route.limiter != nil {
+			route.limiter.Stop()
+		}
+	}
+}
\ No newline at end of file
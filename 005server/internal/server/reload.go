@@ -0,0 +1,126 @@
+package server
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/samnart1/GoLang-Projects/005server/internal/auth"
+	"github.com/samnart1/GoLang-Projects/005server/internal/certs"
+	"github.com/samnart1/GoLang-Projects/005server/internal/config"
+)
+
+// Reload applies the settings from cfg that can change while serving: log
+// level, CORS, security headers, rate limits, auth, templates and
+// certificates. Everything is loaded before any of it is applied, so a
+// failed reload leaves the running configuration untouched. Other changes
+// are logged and only take effect after a restart.
+func (s *Server) Reload(cfg *config.Config) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	// a bad htpasswd file or key fails the reload before anything is applied
+	guard, err := auth.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to set up auth: %w", err)
+	}
+
+	pages, err := s.templates.Load()
+	if err != nil {
+		return fmt.Errorf("failed to reload templates: %w", err)
+	}
+
+	var bundle *certs.Bundle
+	if s.certs != nil {
+		if bundle, err = s.certs.Load(); err != nil {
+			return fmt.Errorf("failed to reload certificates: %w", err)
+		}
+	}
+
+	s.auth.Store(guard)
+	s.logger.SetLevel(cfg.LogLevel)
+	s.cors.Store(newCORSPolicies(cfg))
+	s.security.Store(newSecurityHeaders(cfg.Security))
+	s.templates.Use(pages)
+	if bundle != nil {
+		s.certs.Use(bundle)
+	}
+
+	// rebuilding the limiters refills every client's bucket, so they are
+	// kept unless the limits themselves changed
+	if rateLimitsChanged(s.applied, cfg) {
+		var limits *rateLimits
+		if cfg.EnableRateLimit {
+			limits = newRateLimits(cfg)
+		}
+		if old := s.rateLimits.Swap(limits); old != nil {
+			old.stop()
+		}
+	}
+
+	for _, name := range restartRequired(s.applied, cfg) {
+		s.logger.Warn("Setting changed but needs a restart", "setting", name)
+	}
+	s.applied = cfg
+
+	s.logger.Info("Configuration reloaded",
+		"log_level", cfg.LogLevel,
+		"cors", cfg.CORS.Enabled,
+		"cors_routes", len(cfg.CORSRoutes),
+		"rate_limit", cfg.EnableRateLimit,
+		"auth_routes", len(cfg.AuthRoutes),
+	)
+	return nil
+}
+
+func rateLimitsChanged(old, cfg *config.Config) bool {
+	return old.EnableRateLimit != cfg.EnableRateLimit ||
+		old.RateLimitRPS != cfg.RateLimitRPS ||
+		old.RateLimitBurst != cfg.RateLimitBurst ||
+		old.RateLimitHeader != cfg.RateLimitHeader ||
+		old.RateLimitIdle != cfg.RateLimitIdle ||
+		!reflect.DeepEqual(old.RateLimitRoutes, cfg.RateLimitRoutes)
+}
+
+// restartRequired lists the settings that differ between old and cfg and
+// cannot be applied by Reload.
+func restartRequired(old, cfg *config.Config) []string {
+	var names []string
+	check := func(name string, changed bool) {
+		if changed {
+			names = append(names, name)
+		}
+	}
+
+	check("PORT", old.Port != cfg.Port)
+	check("ENVIRONMENT", old.Environment != cfg.Environment)
+	check("READ_TIMEOUT", old.ReadTimeout != cfg.ReadTimeout)
+	check("WRITE_TIMEOUT", old.WriteTimeout != cfg.WriteTimeout)
+	check("IDLE_TIMEOUT", old.IdleTimeout != cfg.IdleTimeout)
+	check("TRUSTED_PROXIES", !reflect.DeepEqual(old.TrustedProxies, cfg.TrustedProxies))
+	check("ENABLE_METRICS", old.EnableMetrics != cfg.EnableMetrics)
+	check("HEALTH_TIMEOUT", old.HealthTimeout != cfg.HealthTimeout)
+	check("STATIC_DIR", old.StaticDir != cfg.StaticDir)
+	check("STATIC_MAX_AGE", old.StaticMaxAge != cfg.StaticMaxAge)
+	check("STATIC_LISTING", old.StaticListing != cfg.StaticListing)
+	check("STATIC_COMPRESS", old.StaticCompress != cfg.StaticCompress)
+	check("ENABLE_COMPRESSION", old.EnableCompression != cfg.EnableCompression)
+	check("COMPRESSION_MIN_SIZE", old.CompressionMinSize != cfg.CompressionMinSize)
+	check("COMPRESSION_TYPES", !reflect.DeepEqual(old.CompressionTypes, cfg.CompressionTypes))
+	check("COMPRESSION_ENCODINGS", !reflect.DeepEqual(old.CompressionEncodings, cfg.CompressionEncodings))
+	check("ENABLE_ETAGS", old.EnableETags != cfg.EnableETags)
+	check("TEMPLATE_DIR", old.TemplateDir != cfg.TemplateDir)
+	check("TEMPLATE_RELOAD", old.TemplateReload != cfg.TemplateReload)
+	check("TLS_CERT_FILE", old.TLSCertFile != cfg.TLSCertFile)
+	check("TLS_KEY_FILE", old.TLSKeyFile != cfg.TLSKeyFile)
+	check("TLS_MIN_VERSION", old.TLSMinVersion != cfg.TLSMinVersion)
+	check("TLS_CIPHER_POLICY", old.TLSCipherPolicy != cfg.TLSCipherPolicy)
+	check("TLS_CLIENT_CA", old.TLSClientCA != cfg.TLSClientCA)
+	check("TLS_CLIENT_AUTH", old.TLSClientAuth != cfg.TLSClientAuth)
+	check("ENABLE_HTTP2", old.EnableHTTP2 != cfg.EnableHTTP2)
+	check("HTTP_REDIRECT_PORT", old.RedirectPort != cfg.RedirectPort)
+	check("PROXY_ROUTES", !reflect.DeepEqual(old.ProxyRoutes, cfg.ProxyRoutes))
+	check("PROXY_HEALTH_PATH", old.ProxyHealthPath != cfg.ProxyHealthPath)
+	check("PROXY_HEALTH_INTERVAL", old.ProxyHealthInterval != cfg.ProxyHealthInterval)
+
+	return names
+}
\ No newline at end of file
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/samnart1/GoLang-Projects/005server/internal/config"
+)
+
+// securityHeaders are the header values sent with every response.
+type securityHeaders struct {
+	enabled	bool
+	headers	map[string]string
+	// hsts is only sent over TLS, browsers ignore it on plain http
+	hsts	string
+}
+
+func newSecurityHeaders(cfg config.SecurityHeaders) *securityHeaders {
+	sh := &securityHeaders{
+		enabled: cfg.Enabled,
+		headers: map[string]string{"X-Content-Type-Options": "nosniff"},
+	}
+
+	if cfg.ContentSecurityPolicy != "" {
+		sh.headers["Content-Security-Policy"] = cfg.ContentSecurityPolicy
+	}
+	if cfg.FrameOptions != "" {
+		sh.headers["X-Frame-Options"] = cfg.FrameOptions
+	}
+	if cfg.ReferrerPolicy != "" {
+		sh.headers["Referrer-Policy"] = cfg.ReferrerPolicy
+	}
+
+	if cfg.HSTSMaxAge > 0 {
+		sh.hsts = "max-age=" + strconv.Itoa(int(cfg.HSTSMaxAge.Seconds()))
+		if cfg.HSTSIncludeSubdomains {
+			sh.hsts += "; includeSubDomains"
+		}
+		if cfg.HSTSPreload {
+			sh.hsts += "; preload"
+		}
+	}
+
+	return sh
+}
+
+func (s *Server) securityHeadersMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sh := s.security.Load()
+		if !sh.enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		for name, value := range sh.headers {
+			w.Header().Set(name, value)
+		}
+		if r.TLS != nil && sh.hsts != "" {
+			w.Header().Set("Strict-Transport-Security", sh.hsts)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
\ No newline at end of file
@@ -3,39 +3,148 @@ package server
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"sync"
+	"sync/atomic"
 
+	"github.com/samnart1/GoLang-Projects/005server/internal/auth"
+	"github.com/samnart1/GoLang-Projects/005server/internal/certs"
 	"github.com/samnart1/GoLang-Projects/005server/internal/config"
+	"github.com/samnart1/GoLang-Projects/005server/internal/health"
 	"github.com/samnart1/GoLang-Projects/005server/internal/logger"
+	"github.com/samnart1/GoLang-Projects/005server/internal/proxy"
+	"github.com/samnart1/GoLang-Projects/005server/internal/static"
+	"github.com/samnart1/GoLang-Projects/005server/internal/templates"
 )
 
 type Server struct {
 	httpServer *http.Server
 	config *config.Config
+	// applied is the configuration last passed to Reload. The server keeps
+	// running with config for settings that need a restart.
+	applied *config.Config
+	reloadMu sync.Mutex
 	logger *logger.Logger
+	proxies *trustedProxies
+	rateLimits atomic.Pointer[rateLimits]
+	auth atomic.Pointer[auth.Guard]
+	cors atomic.Pointer[corsPolicies]
+	security atomic.Pointer[securityHeaders]
+	checker *health.Checker
+	metrics *health.Metrics
+	templates *templates.Renderer
+	certs *certs.Reloader
+	redirectServer *http.Server
+	upstreams []*proxy.Route
+	// listeners are kept by name so they can be handed to a new process
+	mu sync.Mutex
+	listeners map[string]net.Listener
 }
 
-func New(cfg *config.Config, log *logger.Logger) *Server {
+func New(cfg *config.Config, log *logger.Logger) (*Server, error) {
+	proxies, err := newTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
+	renderer, err := templates.New(cfg.TemplateDir, cfg.TemplateReload, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load templates: %w", err)
+	}
+
 	s := &Server{
 		config: cfg,
+		applied: cfg,
+		templates: renderer,
 		logger: log,
+		proxies: proxies,
+		checker: health.NewChecker("1.0.0", cfg.HealthTimeout),
+	}
+
+	if cfg.EnableMetrics {
+		s.metrics = health.NewMetrics()
+	}
+
+	if err := s.setupProxies(); err != nil {
+		return nil, err
 	}
 
+	s.registerChecks()
+
+	if cfg.EnableRateLimit {
+		s.rateLimits.Store(newRateLimits(cfg))
+	}
+	s.cors.Store(newCORSPolicies(cfg))
+	s.security.Store(newSecurityHeaders(cfg.Security))
+
+	guard, err := auth.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up auth: %w", err)
+	}
+	s.auth.Store(guard)
+
 	s.httpServer = &http.Server{
 		Addr: ":" + cfg.Port,
 		Handler: s.setupRoutes(),
 		ReadTimeout: cfg.ReadTimeout,
 		WriteTimeout: cfg.WriteTimeout,
 		IdleTimeout: cfg.IdleTimeout,
+		Protocols: s.protocols(),
+	}
+
+	if cfg.TLSEnabled() {
+		if err := s.setupTLS(); err != nil {
+			return nil, fmt.Errorf("failed to set up tls: %w", err)
+		}
 	}
 
-	return s
+	return s, nil
 }
 
 func (s *Server) Start() error {
-	s.logger.Info("Server starting", "address", s.httpServer.Addr)
+	ln, err := s.listen("main", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("Server failed to start: %w", err)
+	}
+
+	var redirectLn net.Listener
+	if s.redirectServer != nil {
+		if redirectLn, err = s.listen("redirect", s.redirectServer.Addr); err != nil {
+			ln.Close()
+			return fmt.Errorf("Server failed to start: %w", err)
+		}
+	}
+
+	s.logger.Info("Server starting", "address", s.httpServer.Addr, "tls", s.certs != nil)
+	s.checker.SetReady(true)
+
+	for _, route := range s.upstreams {
+		route.Start()
+	}
+
+	// the sockets are accepting from here on, so a parent process waiting
+	// on us can stop serving
+	s.notifyReady()
+
+	if s.certs == nil {
+		if err := s.httpServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("Server failed to start: %w", err)
+		}
+		return nil
+	}
+
+	if redirectLn != nil {
+		go func() {
+			s.logger.Info("Redirecting http to https", "address", s.redirectServer.Addr)
+			if err := s.redirectServer.Serve(redirectLn); err != nil && err != http.ErrServerClosed {
+				s.logger.Error("Redirect server failed", "error", err)
+			}
+		}()
+	}
 
-	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	// the certificate comes from TLSConfig.GetCertificate
+	if err := s.httpServer.ServeTLS(ln, "", ""); err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("Server failed to start: %w", err)
 	}
 
@@ -44,12 +153,37 @@ func (s *Server) Start() error {
 
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("Server shutting down...")
+	s.checker.SetReady(false)
 
 	//shutdown the http server
 	if err := s.httpServer.Shutdown(ctx); err != nil {
 		return fmt.Errorf("server shutdown failed: %w", err)
 	}
 
+	if s.redirectServer != nil {
+		if err := s.redirectServer.Shutdown(ctx); err != nil {
+			return fmt.Errorf("redirect server shutdown failed: %w", err)
+		}
+	}
+
+	if limits := s.rateLimits.Load(); limits != nil {
+		limits.stop()
+	}
+
+	for _, route := range s.upstreams {
+		route.Close()
+	}
+
+	if s.certs != nil {
+		if err := s.certs.Close(); err != nil {
+			s.logger.Error("Failed to stop certificate watcher", "error", err)
+		}
+	}
+
+	if err := s.templates.Close(); err != nil {
+		s.logger.Error("Failed to stop template watcher", "error", err)
+	}
+
 	s.logger.Info("Server shutdown complete")
 	return nil
 }
@@ -79,18 +213,90 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 
 	mux.HandleFunc("/health", s.handleHealth)
 	mux.HandleFunc("/ready", s.handleReady)
+
+	if s.metrics != nil {
+		mux.Handle("/metrics", s.metrics.Handler())
+	}
+
+	for _, route := range s.upstreams {
+		mux.Handle(route.Prefix(), route)
+	}
 	
-	fileServer := http.FileServer(http.Dir(s.config.StaticDir))
-	mux.Handle("/static/", http.StripPrefix("/static/", fileServer))
+	fileServer := static.New(s.config.StaticDir, static.Options{
+		MaxAge: s.config.StaticMaxAge,
+		Listing: s.config.StaticListing,
+		Compress: s.config.StaticCompress,
+	})
+	mux.Handle("/static/", http.StripPrefix("/static", fileServer))
+}
+
+// builtinPrefixes are subtree patterns already taken by registerRoutes.
+var builtinPrefixes = map[string]bool{"/": true, "/static/": true}
+
+func (s *Server) setupProxies() error {
+	for _, cfg := range s.config.ProxyRoutes {
+		if builtinPrefixes[cfg.Prefix] {
+			return fmt.Errorf("proxy route %s conflicts with a built-in route", cfg.Prefix)
+		}
+
+		route, err := proxy.NewRoute(cfg, s.config.ProxyHealthPath, s.config.ProxyHealthInterval, s.logger)
+		if err != nil {
+			return fmt.Errorf("failed to set up proxy route %s: %w", cfg.Prefix, err)
+		}
+		s.upstreams = append(s.upstreams, route)
+	}
+
+	return nil
+}
+
+// Checker exposes the health registry so other components can register their
+// own checks.
+func (s *Server) Checker() *health.Checker {
+	return s.checker
+}
+
+func (s *Server) registerChecks() {
+	s.checker.Register("templates", 0, health.DirCheck(s.config.TemplateDir))
+	s.checker.Register("static", 0, health.DirCheck(s.config.StaticDir))
+
+	for _, route := range s.upstreams {
+		s.checker.RegisterReadiness("proxy "+route.Prefix(), 0, route.Check)
+	}
 }
 
 func (s *Server) applyMiddleware(handler http.Handler) http.Handler {
+	if s.metrics != nil {
+		handler = s.routeMiddleware(handler)
+	}
+
+	handler = s.spanMiddleware("handler", handler)
 	handler = s.recoveryMiddleware(handler)
+	handler = s.authMiddleware(handler)
+
+	// rate limiting and CORS are always in the chain since both can be
+	// switched on and off by a reload
+	handler = s.rateLimitMiddleware(handler)
+
+	if s.config.EnableCompression {
+		handler = s.compressionMiddleware(handler)
+	}
+
+	// outside compression, so the ETag is computed over the encoded body
+	if s.config.EnableETags {
+		handler = s.conditionalMiddleware(handler)
+	}
+
 	handler = s.loggingMiddleware(handler)
 
-	if s.config.EnableCORS {
-		handler = s.corsMiddleware(handler)
+	if s.metrics != nil {
+		handler = s.metricsMiddleware(handler)
 	}
 
+	handler = s.corsMiddleware(handler)
+	handler = s.securityHeadersMiddleware(handler)
+
+	// outermost, so every response carries the request id
+	handler = s.requestIDMiddleware(handler)
+
 	return handler
 }
\ No newline at end of file
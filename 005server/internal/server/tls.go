@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/samnart1/GoLang-Projects/005server/internal/certs"
+)
+
+func (s *Server) setupTLS() error {
+	reloader, err := certs.NewReloader(s.config.TLSCertFile, s.config.TLSKeyFile, s.config.TLSClientCA, s.logger)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := certs.TLSConfig(reloader, certs.Options{
+		MinVersion: s.config.TLSMinVersion,
+		CipherPolicy: s.config.TLSCipherPolicy,
+		ClientAuth: s.config.TLSClientAuth,
+		HTTP2: s.config.EnableHTTP2,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := reloader.Watch(); err != nil {
+		s.logger.Warn("Certificate files are not watched, reload with SIGHUP", "error", err)
+	}
+
+	s.certs = reloader
+	s.httpServer.TLSConfig = tlsConfig
+
+	if s.config.RedirectPort != "" {
+		s.redirectServer = &http.Server{
+			Addr: ":" + s.config.RedirectPort,
+			Handler: http.HandlerFunc(s.redirectToHTTPS),
+			ReadTimeout: s.config.ReadTimeout,
+			WriteTimeout: s.config.WriteTimeout,
+			IdleTimeout: s.config.IdleTimeout,
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) redirectToHTTPS(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if s.config.Port != "443" {
+		host = net.JoinHostPort(host, s.config.Port)
+	}
+
+	target := "https://" + host + r.URL.RequestURI()
+	http.Redirect(w, r, target, http.StatusMovedPermanently)
+}
+
+func (s *Server) protocols() *http.Protocols {
+	p := new(http.Protocols)
+	p.SetHTTP1(true)
+	p.SetHTTP2(s.config.EnableHTTP2)
+	return p
+}
\ No newline at end of file
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/samnart1/GoLang-Projects/005server/internal/config"
+)
+
+const (
+	// listenFDsEnv names the sockets passed to a new process, for example
+	// "main:3,redirect:4"
+	listenFDsEnv = "SERVER_LISTEN_FDS"
+	// readyFDEnv is the pipe the new process writes to once it is serving
+	readyFDEnv = "SERVER_READY_FD"
+
+	upgradeTimeout = 30 * time.Second
+)
+
+// listen opens the named listener, reusing the socket inherited from the
+// previous process when there is one.
+func (s *Server) listen(name, addr string) (net.Listener, error) {
+	var ln net.Listener
+	if fd, ok := inheritedFDs()[name]; ok {
+		f := os.NewFile(uintptr(fd), name)
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to use inherited %s listener: %w", name, err)
+		}
+		s.logger.Info("Using inherited listener", "name", name, "address", l.Addr().String())
+		ln = l
+	} else {
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		ln = l
+	}
+
+	s.mu.Lock()
+	if s.listeners == nil {
+		s.listeners = make(map[string]net.Listener)
+	}
+	s.listeners[name] = ln
+	s.mu.Unlock()
+
+	return ln, nil
+}
+
+func inheritedFDs() map[string]int {
+	fds := make(map[string]int)
+	for _, item := range strings.Split(os.Getenv(listenFDsEnv), ",") {
+		name, value, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		if fd, err := strconv.Atoi(value); err == nil {
+			fds[name] = fd
+		}
+	}
+	return fds
+}
+
+// notifyReady tells the parent process, if any, that this one has taken over.
+func (s *Server) notifyReady() {
+	value := os.Getenv(readyFDEnv)
+	if value == "" {
+		return
+	}
+
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		return
+	}
+
+	f := os.NewFile(uintptr(fd), "ready")
+	if _, err := f.Write([]byte{1}); err != nil {
+		s.logger.Warn("Failed to notify parent process", "error", err)
+	}
+	f.Close()
+}
+
+// Upgrade starts a new copy of the running binary with the listening sockets
+// and waits until it is serving. On success the caller should shut this
+// server down, which drains in-flight requests while the new process accepts
+// new connections. On failure this server keeps running.
+func (s *Server) Upgrade() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find executable: %w", err)
+	}
+
+	// raw descriptors are passed rather than *os.File, since os.File.Fd
+	// would switch the shared sockets to blocking mode under our own
+	// listeners
+	fds := []uintptr{os.Stdin.Fd(), os.Stdout.Fd(), os.Stderr.Fd()}
+	var names []string
+
+	s.mu.Lock()
+	for name, ln := range s.listeners {
+		fd, err := rawFD(ln)
+		if err != nil {
+			s.mu.Unlock()
+			return fmt.Errorf("listener %s cannot be passed on: %w", name, err)
+		}
+		names = append(names, name+":"+strconv.Itoa(len(fds)))
+		fds = append(fds, fd)
+	}
+	s.mu.Unlock()
+
+	if len(names) == 0 {
+		return fmt.Errorf("server is not listening")
+	}
+
+	ready, readyW, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to create ready pipe: %w", err)
+	}
+	defer ready.Close()
+	readyFD := len(fds)
+	fds = append(fds, readyW.Fd())
+
+	var env []string
+	for _, kv := range config.ProcessEnv() {
+		if strings.HasPrefix(kv, listenFDsEnv+"=") || strings.HasPrefix(kv, readyFDEnv+"=") {
+			continue
+		}
+		env = append(env, kv)
+	}
+	env = append(env,
+		listenFDsEnv+"="+strings.Join(names, ","),
+		readyFDEnv+"="+strconv.Itoa(readyFD),
+	)
+
+	pid, err := syscall.ForkExec(exe, os.Args, &syscall.ProcAttr{
+		Env: env,
+		Files: fds,
+	})
+	// only the child may hold the write end, so a child that exits early
+	// shows up as EOF
+	readyW.Close()
+	if err != nil {
+		return fmt.Errorf("failed to start new process: %w", err)
+	}
+	s.logger.Info("Started new process", "pid", pid)
+
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 1)
+		_, err := io.ReadFull(ready, buf)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			proc.Wait()
+			return fmt.Errorf("new process exited before it was ready")
+		}
+	case <-time.After(upgradeTimeout):
+		proc.Kill()
+		proc.Wait()
+		return fmt.Errorf("new process was not ready after %s", upgradeTimeout)
+	}
+
+	// the new process outlives this one
+	return proc.Release()
+}
+
+func rawFD(ln net.Listener) (uintptr, error) {
+	sc, ok := ln.(syscall.Conn)
+	if !ok {
+		return 0, fmt.Errorf("not a socket")
+	}
+
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	var fd uintptr
+	if err := rc.Control(func(f uintptr) { fd = f }); err != nil {
+		return 0, err
+	}
+	return fd, nil
+}
\ No newline at end of file
@@ -0,0 +1,275 @@
+package static
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samnart1/GoLang-Projects/005server/internal/compress"
+)
+
+type Options struct {
+	// MaxAge is sent in Cache-Control. Zero asks clients to revalidate on
+	// every request.
+	MaxAge		time.Duration
+	// Listing renders an index for directories without an index.html.
+	Listing		bool
+	// Compress enables on-the-fly compression when no precompressed
+	// .br/.gz sibling exists.
+	Compress	bool
+	// MinCompressSize is the smallest file that is compressed on the fly.
+	MinCompressSize	int64
+}
+
+// encodings in order of preference, with the suffix of precompressed files.
+var encodings = []struct {
+	name	string
+	suffix	string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+type cacheKey struct {
+	path		string
+	encoding	string
+}
+
+type cacheEntry struct {
+	modTime	time.Time
+	size	int64
+	data	[]byte
+}
+
+// Handler serves files from a directory with validators, cache headers and
+// content-encoding negotiation.
+type Handler struct {
+	root	string
+	opts	Options
+	mu		sync.RWMutex
+	cache	map[cacheKey]*cacheEntry
+}
+
+func New(root string, opts Options) *Handler {
+	if opts.MinCompressSize <= 0 {
+		opts.MinCompressSize = 1024
+	}
+
+	return &Handler{
+		root: root,
+		opts: opts,
+		cache: make(map[cacheKey]*cacheEntry),
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := path.Clean("/" + r.URL.Path)
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	full := filepath.Join(h.root, filepath.FromSlash(name))
+	info, err := os.Stat(full)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if info.IsDir() {
+		if !strings.HasSuffix(r.URL.Path, "/") {
+			http.Redirect(w, r, path.Base(r.URL.Path)+"/", http.StatusMovedPermanently)
+			return
+		}
+
+		index := filepath.Join(full, "index.html")
+		if indexInfo, err := os.Stat(index); err == nil && !indexInfo.IsDir() {
+			full, info = index, indexInfo
+		} else if h.opts.Listing {
+			h.serveListing(w, r, full, name)
+			return
+		} else {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	h.serveFile(w, r, full, info)
+}
+
+func (h *Handler) serveFile(w http.ResponseWriter, r *http.Request, full string, info os.FileInfo) {
+	header := w.Header()
+
+	contentType := mime.TypeByExtension(filepath.Ext(full))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header.Set("Content-Type", contentType)
+	header.Set("Cache-Control", h.cacheControl())
+	header.Add("Vary", "Accept-Encoding")
+
+	etag := fmt.Sprintf("%x-%x", info.ModTime().UnixNano(), info.Size())
+	accepted := acceptedEncodings(r.Header.Get("Accept-Encoding"))
+
+	for _, enc := range encodings {
+		if !accepted[enc.name] {
+			continue
+		}
+
+		// a precompressed sibling wins if it is at least as new as the file
+		if pre, err := os.Stat(full + enc.suffix); err == nil && !pre.ModTime().Before(info.ModTime()) {
+			f, err := os.Open(full + enc.suffix)
+			if err != nil {
+				break
+			}
+			defer f.Close()
+
+			header.Set("Content-Encoding", enc.name)
+			header.Set("ETag", strconv.Quote(etag+"-"+enc.name))
+			http.ServeContent(w, r, "", info.ModTime(), f)
+			return
+		}
+
+		if h.opts.Compress && info.Size() >= h.opts.MinCompressSize && compressible(contentType) {
+			data, err := h.compressed(full, info, enc.name)
+			if err != nil {
+				break
+			}
+
+			header.Set("Content-Encoding", enc.name)
+			header.Set("ETag", strconv.Quote(etag+"-"+enc.name))
+			http.ServeContent(w, r, "", info.ModTime(), bytes.NewReader(data))
+			return
+		}
+	}
+
+	f, err := os.Open(full)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+
+	header.Set("ETag", strconv.Quote(etag))
+	http.ServeContent(w, r, "", info.ModTime(), f)
+}
+
+func (h *Handler) cacheControl() string {
+	if h.opts.MaxAge <= 0 {
+		return "no-cache"
+	}
+	return fmt.Sprintf("public, max-age=%d", int(h.opts.MaxAge.Seconds()))
+}
+
+// compressed returns the encoded file, reusing the cached copy until the file
+// changes.
+func (h *Handler) compressed(full string, info os.FileInfo, encoding string) ([]byte, error) {
+	key := cacheKey{path: full, encoding: encoding}
+
+	h.mu.RLock()
+	entry, ok := h.cache[key]
+	h.mu.RUnlock()
+	if ok && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+		return entry.data, nil
+	}
+
+	raw, err := os.ReadFile(full)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := compress.NewWriter(&buf, encoding)
+	if _, err := zw.Write(raw); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	h.cache[key] = &cacheEntry{modTime: info.ModTime(), size: info.Size(), data: buf.Bytes()}
+	h.mu.Unlock()
+
+	return buf.Bytes(), nil
+}
+
+var listingTemplate = template.Must(template.New("listing").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="UTF-8"><title>Index of {{.Path}}</title></head>
+<body>
+<h1>Index of {{.Path}}</h1>
+<ul>
+{{if ne .Path "/"}}<li><a href="../">../</a></li>{{end}}
+{{range .Entries}}<li><a href="{{.Href}}">{{.Name}}</a></li>
+{{end}}</ul>
+</body>
+</html>
+`))
+
+type listingEntry struct {
+	Name	string
+	Href	string
+}
+
+func (h *Handler) serveListing(w http.ResponseWriter, r *http.Request, dir, name string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	var list []listingEntry
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		display := entry.Name()
+		if entry.IsDir() {
+			display += "/"
+		}
+		list = append(list, listingEntry{Name: display, Href: "./" + (&url.URL{Path: display}).String()})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	if err := listingTemplate.Execute(w, map[string]interface{}{"Path": name, "Entries": list}); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// acceptedEncodings parses Accept-Encoding for the encodings served here.
+func acceptedEncodings(header string) map[string]bool {
+	names := make([]string, len(encodings))
+	for i, enc := range encodings {
+		names[i] = enc.name
+	}
+	return compress.Accepted(header, names)
+}
+
+var compressibleTypes = compress.Types(compress.DefaultTypes)
+
+func compressible(contentType string) bool {
+	return compressibleTypes.Match(contentType)
+}
\ No newline at end of file
@@ -0,0 +1,220 @@
+package templates
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/samnart1/GoLang-Projects/005server/internal/logger"
+)
+
+const (
+	layoutsDir	= "layouts"
+	partialsDir	= "partials"
+)
+
+// Renderer holds the parsed page templates. Every page in the template
+// directory gets its own set containing all layouts and partials, so pages
+// can override the blocks a layout defines.
+type Renderer struct {
+	dir		string
+	logger	*logger.Logger
+	mu		sync.RWMutex
+	pages	map[string]*template.Template
+	loaded	time.Time
+	watcher	*fsnotify.Watcher
+	done	chan struct{}
+}
+
+// New parses every template in dir. With reload set, the templates are parsed
+// again whenever a file in dir changes.
+func New(dir string, reload bool, log *logger.Logger) (*Renderer, error) {
+	r := &Renderer{
+		dir: dir,
+		logger: log,
+		done: make(chan struct{}),
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	if reload {
+		if err := r.watch(); err != nil {
+			return nil, fmt.Errorf("failed to watch templates: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// Set is a parsed set of pages that is not yet being rendered.
+type Set struct {
+	pages	map[string]*template.Template
+}
+
+// Reload parses the templates from disk. The previous set is kept if parsing
+// fails.
+func (r *Renderer) Reload() error {
+	set, err := r.Load()
+	if err != nil {
+		return err
+	}
+
+	r.Use(set)
+	return nil
+}
+
+// Load parses the templates from disk without putting them into use, so a
+// caller can stage them alongside other changes.
+func (r *Renderer) Load() (*Set, error) {
+	pages, err := parse(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Set{pages: pages}, nil
+}
+
+// Use replaces the pages being rendered with set.
+func (r *Renderer) Use(set *Set) {
+	r.mu.Lock()
+	r.pages = set.pages
+	r.loaded = time.Now()
+	r.mu.Unlock()
+}
+
+// ModTime is when the templates were last parsed, which bounds the age of
+// pages that render no per-request data.
+func (r *Renderer) ModTime() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.loaded
+}
+
+// Render executes the named page into w.
+func (r *Renderer) Render(w io.Writer, name string, data interface{}) error {
+	r.mu.RLock()
+	tmpl, ok := r.pages[name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("template %q not found", name)
+	}
+
+	return tmpl.ExecuteTemplate(w, name, data)
+}
+
+func (r *Renderer) Close() error {
+	if r.watcher == nil {
+		return nil
+	}
+
+	close(r.done)
+	return r.watcher.Close()
+}
+
+func parse(dir string) (map[string]*template.Template, error) {
+	var shared []string
+	for _, sub := range []string{layoutsDir, partialsDir} {
+		files, err := filepath.Glob(filepath.Join(dir, sub, "*.html"))
+		if err != nil {
+			return nil, err
+		}
+		shared = append(shared, files...)
+	}
+
+	pageFiles, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return nil, err
+	}
+	if len(pageFiles) == 0 {
+		return nil, fmt.Errorf("no templates found in %s", dir)
+	}
+
+	pages := make(map[string]*template.Template, len(pageFiles))
+	for _, page := range pageFiles {
+		name := filepath.Base(page)
+
+		tmpl := template.New(name)
+		if len(shared) > 0 {
+			if tmpl, err = tmpl.ParseFiles(shared...); err != nil {
+				return nil, fmt.Errorf("failed to parse layouts for %s: %w", name, err)
+			}
+		}
+		if tmpl, err = tmpl.ParseFiles(page); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		pages[name] = tmpl
+	}
+
+	return pages, nil
+}
+
+func (r *Renderer) watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range []string{r.dir, filepath.Join(r.dir, layoutsDir), filepath.Join(r.dir, partialsDir)} {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return err
+		}
+	}
+
+	r.watcher = watcher
+	go r.watchLoop()
+
+	return nil
+}
+
+func (r *Renderer) watchLoop() {
+	// editors often write a file in several steps, so wait for things to
+	// settle before parsing
+	const settle = 100 * time.Millisecond
+
+	var timer *time.Timer
+	var pending <-chan time.Time
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case event, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Ext(event.Name) != ".html" {
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(settle)
+			} else {
+				timer.Reset(settle)
+			}
+			pending = timer.C
+		case <-pending:
+			pending = nil
+			if err := r.Reload(); err != nil {
+				r.logger.Error("Template reload failed", "error", err)
+				continue
+			}
+			r.logger.Info("Templates reloaded", "dir", r.dir)
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			r.logger.Error("Template watcher error", "error", err)
+		}
+	}
+}
\ No newline at end of file
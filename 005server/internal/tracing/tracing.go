@@ -0,0 +1,180 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	RequestIDHeader		= "X-Request-ID"
+	TraceparentHeader	= "traceparent"
+
+	maxRequestIDLength	= 128
+)
+
+type contextKey struct{}
+
+// Span is one timed step of handling a request.
+type Span struct {
+	Name		string
+	Start		time.Duration
+	Duration	time.Duration
+}
+
+// Trace identifies a request across services. It carries the caller's
+// X-Request-ID and W3C trace context, and can record spans for a timing
+// breakdown.
+type Trace struct {
+	RequestID	string
+	TraceID		string
+	ParentID	string
+	SpanID		string
+	Flags		string
+
+	started		time.Time
+	record		bool
+	mu			sync.Mutex
+	spans		[]Span
+}
+
+// FromRequest continues the trace of an incoming request, or starts a new
+// one. Spans are only kept when record is set.
+func FromRequest(r *http.Request, record bool) *Trace {
+	t := &Trace{
+		SpanID: randomHex(8),
+		Flags: "01",
+		started: time.Now(),
+		record: record,
+	}
+
+	if traceID, parentID, flags, ok := parseTraceparent(r.Header.Get(TraceparentHeader)); ok {
+		t.TraceID, t.ParentID, t.Flags = traceID, parentID, flags
+	} else {
+		t.TraceID = randomHex(16)
+	}
+
+	if id := r.Header.Get(RequestIDHeader); validRequestID(id) {
+		t.RequestID = id
+	} else {
+		t.RequestID = t.TraceID
+	}
+
+	return t
+}
+
+// Traceparent is the header to pass on to downstream services and back to
+// the client, naming this server's span as the parent.
+func (t *Trace) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-%s", t.TraceID, t.SpanID, t.Flags)
+}
+
+// StartSpan begins a span and returns the function that ends it.
+func (t *Trace) StartSpan(name string) func() {
+	if t == nil || !t.record {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		span := Span{
+			Name: name,
+			Start: start.Sub(t.started),
+			Duration: time.Since(start),
+		}
+
+		t.mu.Lock()
+		t.spans = append(t.spans, span)
+		t.mu.Unlock()
+	}
+}
+
+func (t *Trace) Spans() []Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Span(nil), t.spans...)
+}
+
+func (t *Trace) Elapsed() time.Duration {
+	return time.Since(t.started)
+}
+
+func NewContext(ctx context.Context, t *Trace) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the request's trace, or nil outside a request.
+func FromContext(ctx context.Context) *Trace {
+	t, _ := ctx.Value(contextKey{}).(*Trace)
+	return t
+}
+
+// StartSpan begins a span on the trace in ctx, if there is one.
+func StartSpan(ctx context.Context, name string) func() {
+	return FromContext(ctx).StartSpan(name)
+}
+
+func parseTraceparent(header string) (traceID, parentID, flags string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 {
+		return "", "", "", false
+	}
+
+	version, traceID, parentID, flags := parts[0], parts[1], parts[2], parts[3]
+	// version ff is invalid, and version 00 must have exactly four fields
+	if !isHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return "", "", "", false
+	}
+	if !isHex(traceID, 32) || traceID == strings.Repeat("0", 32) {
+		return "", "", "", false
+	}
+	if !isHex(parentID, 16) || parentID == strings.Repeat("0", 16) {
+		return "", "", "", false
+	}
+	if !isHex(flags, 2) {
+		return "", "", "", false
+	}
+
+	return traceID, parentID, flags, true
+}
+
+func isHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// validRequestID accepts short printable ASCII IDs, so a caller can't inject
+// anything odd into logs or headers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// fall back to something unique enough rather than failing the request
+		return fmt.Sprintf("%0*x", n*2, time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
\ No newline at end of file
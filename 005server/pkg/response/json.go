@@ -17,11 +17,18 @@ func JSON(w http.ResponseWriter, statusCode int, data interface{}) {
 }
 
 func Error(w http.ResponseWriter, statusCode int, message string) {
+	body := map[string]interface{}{
+		"message": 	message,
+		"status":	statusCode,
+	}
+
+	// the request id middleware sets this before any handler runs
+	if requestID := w.Header().Get("X-Request-ID"); requestID != "" {
+		body["request_id"] = requestID
+	}
+
 	errorResponse := map[string]interface{}{
-		"error": map[string]interface{}{
-			"message": 	message,
-			"status":	statusCode,
-		},
+		"error": body,
 	}
 
 	JSON(w, statusCode, errorResponse)
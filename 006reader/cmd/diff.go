@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/samnart1/GoLang/006reader/internal/diff"
+	"github.com/samnart1/GoLang/006reader/internal/formatter"
+	"github.com/samnart1/GoLang/006reader/internal/reader"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFormat			string
+	diffEncoding		string
+	diffColor			string
+	unifiedLines		int
+	ignoreWhitespace	bool
+	diffIgnoreCase		bool
+	briefDiff			bool
+)
+
+// ErrFilesDiffer is returned by the diff command when the files differ.
+var ErrFilesDiffer = errors.New("files differ")
+
+var diffCmd = &cobra.Command{
+	Use: "diff OLD NEW",
+	Short: "Compare two files line by line",
+	Long: `Compare two files and show the differences as a unified diff. Both files are
+	decoded first, so files in different encodings or compressed files compare by
+	their text. The exit status is 1 when the files differ, as with diff
+
+	Examples:
+		go-file-reader diff old.conf new.conf
+		go-file-reader diff -U 1 -w a.txt b.txt
+		go-file-reader diff -f json app.log app.log.1.gz
+		go-file-reader diff -f table old.csv new.csv`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "plain", "output format (plain, json, table)")
+	diffCmd.Flags().StringVarP(&diffEncoding, "encoding", "e", "", "file encoding (default from config)")
+	diffCmd.Flags().StringVar(&diffColor, "color", "auto", "color the diff (auto, always, never)")
+	diffCmd.Flags().IntVarP(&unifiedLines, "unified", "U", 3, "number of unchanged lines to show around each change")
+	diffCmd.Flags().BoolVarP(&ignoreWhitespace, "ignore-all-space", "w", false, "ignore differences in whitespace")
+	diffCmd.Flags().BoolVarP(&diffIgnoreCase, "ignore-case", "i", false, "ignore differences in case")
+	diffCmd.Flags().BoolVarP(&briefDiff, "brief", "q", false, "only report whether the files differ")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if unifiedLines < 0 {
+		return fmt.Errorf("--unified cannot be negative")
+	}
+
+	fileEncoding, err := resolveEncoding(diffEncoding)
+	if err != nil {
+		return err
+	}
+
+	color, err := useColor(diffColor)
+	if err != nil {
+		return err
+	}
+
+	fmtHandler, err := formatter.New(diffFormat, &formatter.Config{
+		MaxWidth:		cfg.Formatter.MaxWidth,
+		Theme:			cfg.Formatter.Theme,
+		ColorOutput:	color,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create formatter: %w", err)
+	}
+	diffFormatter, ok := fmtHandler.(formatter.DiffFormatter)
+	if !ok {
+		return fmt.Errorf("the %s format cannot show a diff", diffFormat)
+	}
+
+	contents, err := readFiles(args, reader.Config{
+		Encoding:	fileEncoding,
+		BufferSize:	cfg.Reader.BufferSize,
+	})
+	if err != nil {
+		return err
+	}
+	oldFile, newFile := contents[0], contents[1]
+
+	result := diff.Compare(diffText(oldFile), diffText(newFile), diff.Options{
+		Context:			unifiedLines,
+		IgnoreWhitespace:	ignoreWhitespace,
+		IgnoreCase:			diffIgnoreCase,
+	})
+	result.OldPath, result.OldTime = args[0], oldFile.Metadata.ModTime
+	result.NewPath, result.NewTime = args[1], newFile.Metadata.ModTime
+
+	if briefDiff {
+		if !result.Equal() {
+			fmt.Printf("Files %s and %s differ\n", args[0], args[1])
+		}
+		return differResult(cmd, result)
+	}
+
+	output, err := diffFormatter.FormatDiff(result)
+	if err != nil {
+		return fmt.Errorf("failed to format diff: %w", err)
+	}
+	if output != "" {
+		fmt.Println(output)
+	}
+
+	return differResult(cmd, result)
+}
+
+// differResult exits with status 1 when the files differ, as diff does,
+// without reporting it as a failure.
+func differResult(cmd *cobra.Command, result *diff.Diff) error {
+	if result.Equal() {
+		return nil
+	}
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return ErrFilesDiffer
+}
+
+func diffText(content *reader.Content) diff.Text {
+	text := diff.Text{Lines: make([]string, len(content.Lines))}
+	for i, line := range content.Lines {
+		text.Lines[i] = line.Content
+	}
+	if n := len(content.Lines); n > 0 {
+		text.NoFinalNewline = content.Lines[n-1].Ending == ""
+	}
+	return text
+}
\ No newline at end of file
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/samnart1/GoLang/006reader/internal/formatter"
+	"github.com/samnart1/GoLang/006reader/internal/pager"
+	"github.com/samnart1/GoLang/006reader/internal/reader"
+	"github.com/spf13/cobra"
+)
+
+var (
+	grepPatterns	[]string
+	grepFormat		string
+	grepLines		bool
+	grepEncoding	string
+	fixedStrings	bool
+	ignoreCase		bool
+	invertMatch		bool
+	beforeContext	int
+	afterContext	int
+	contextLines	int
+	countOnly		bool
+	maxCount		int
+	colorMode		string
+)
+
+var grepCmd = &cobra.Command{
+	Use: "grep [pattern] [file|glob]...",
+	Short: "Show the lines of files that match a pattern",
+	Long: `Search files for lines matching a regular expression or literal text and
+	display them with optional context and highlighted matches
+	
+	Examples:
+		go-file-reader grep error app.log
+		go-file-reader grep -i -C 2 "timeout|refused" "logs/*.log"
+		go-file-reader grep -F -e "[WARN]" -e "[ERROR]" app.log
+		go-file-reader grep --invert-match -c debug app.log
+		go-file-reader grep --format json "user=\w+" app.log`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runGrep,
+}
+
+func init() {
+	rootCmd.AddCommand(grepCmd)
+
+	grepCmd.Flags().StringArrayVarP(&grepPatterns, "regexp", "e", nil, "pattern to search for, may be repeated (all arguments are then files)")
+	grepCmd.Flags().StringVarP(&grepFormat, "format", "f", "plain", "output format (plain, json, table, syntax)")
+	grepCmd.Flags().BoolVarP(&grepLines, "line-number", "n", false, "show line numbers")
+	grepCmd.Flags().StringVar(&grepEncoding, "encoding", "", "file encoding (default from config)")
+	grepCmd.Flags().BoolVarP(&fixedStrings, "fixed-strings", "F", false, "treat patterns as literal text")
+	grepCmd.Flags().BoolVarP(&ignoreCase, "ignore-case", "i", false, "ignore case when matching")
+	grepCmd.Flags().BoolVar(&invertMatch, "invert-match", false, "select lines that do not match")
+	grepCmd.Flags().IntVarP(&beforeContext, "before-context", "B", 0, "lines of context before each match")
+	grepCmd.Flags().IntVarP(&afterContext, "after-context", "A", 0, "lines of context after each match")
+	grepCmd.Flags().IntVarP(&contextLines, "context", "C", 0, "lines of context before and after each match")
+	grepCmd.Flags().BoolVarP(&countOnly, "count", "c", false, "only print the number of matching lines")
+	grepCmd.Flags().IntVarP(&maxCount, "max-count", "m", 0, "stop after this many matching lines (0 for no limit)")
+	grepCmd.Flags().StringVar(&colorMode, "color", "auto", "highlight matches (auto, always, never)")
+}
+
+func runGrep(cmd *cobra.Command, args []string) error {
+	patterns := grepPatterns
+	if len(patterns) == 0 {
+		patterns, args = args[:1], args[1:]
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("no files given to search")
+	}
+
+	files, err := expandPaths(args)
+	if err != nil {
+		return err
+	}
+
+	fileEncoding, err := resolveEncoding(grepEncoding)
+	if err != nil {
+		return err
+	}
+
+	// -A and -B win over -C, as in grep
+	before, after := contextLines, contextLines
+	if cmd.Flags().Changed("before-context") {
+		before = beforeContext
+	}
+	if cmd.Flags().Changed("after-context") {
+		after = afterContext
+	}
+
+	color, err := useColor(colorMode)
+	if err != nil {
+		return err
+	}
+
+	if grepFormat == "hex" {
+		return fmt.Errorf("hex dumps show bytes rather than lines: use read --format hex")
+	}
+
+	readerConfig := reader.Config{
+		Encoding:	fileEncoding,
+		BufferSize:	cfg.Reader.BufferSize,
+		Search:		&reader.SearchConfig{
+			Patterns:	patterns,
+			Literal:	fixedStrings,
+			IgnoreCase:	ignoreCase,
+			Invert:		invertMatch,
+			Before:		before,
+			After:		after,
+			MaxCount:	maxCount,
+		},
+	}
+	if err := readerConfig.Validate(); err != nil {
+		return err
+	}
+
+	contents, err := readFiles(files, readerConfig)
+	if err != nil {
+		return err
+	}
+
+	if countOnly {
+		for _, content := range contents {
+			if len(contents) > 1 {
+				fmt.Printf("%s:", content.Metadata.FilePath)
+			}
+			fmt.Println(content.Metadata.Search.MatchCount)
+		}
+		return nil
+	}
+
+	fmtHandler, err := formatter.New(grepFormat, &formatter.Config{
+		ShowLineNumbers:	grepLines,
+		MaxWidth:			cfg.Formatter.MaxWidth,
+		Theme:				cfg.Formatter.Theme,
+		ColorOutput:		color,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create formatter: %w", err)
+	}
+
+	return printContents(contents, fmtHandler, grepFormat)
+}
+
+func useColor(mode string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		return pager.IsTerminal(os.Stdout), nil
+	default:
+		return false, fmt.Errorf("invalid color mode: %s (use auto, always or never)", mode)
+	}
+}
\ No newline at end of file
@@ -1,11 +1,19 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/samnart1/GoLang/006reader/internal/formatter"
 	"github.com/samnart1/GoLang/006reader/internal/logger"
+	"github.com/samnart1/GoLang/006reader/internal/pager"
+	"github.com/samnart1/GoLang/006reader/internal/parser"
 	"github.com/samnart1/GoLang/006reader/internal/reader"
 	"github.com/spf13/cobra"
 )
@@ -15,78 +23,446 @@ var (
 	lineNumber	bool
 	maxLines	int
 	encoding	string
+	fromLine	int
+	toLine		int
+	offset		int64
+	length		int64
+	tailLines	int
+	usePager	bool
+	parseAs		string
+	columns		[]string
+	sortBy		[]string
+	filters		[]string
+	listMembers	bool
+	readColor	string
 )
 
 var readCmd = &cobra.Command{
-	Use: "read [file]",
+	Use: "read [file|glob]...",
 	Short: "Read and display file contents",
-	Long: `Read and display the contents of a text file with version formatiing options
+	Long: `Read and display the contents of one or more text files with version formatiing options
 	
 	Examples:
 		go-file-reader read file.txt
 		go-file-reader read --format json file.txt
-		go-file-reader read --lines --max-lines 50 file.txt`,
-	Args: cobra.ExactArgs(1),
+		go-file-reader read --lines --max-lines 50 file.txt
+		go-file-reader read "logs/*.log"
+		go-file-reader read --from-line 100 --to-line 200 file.txt
+		go-file-reader read --offset 4096 --length 1024 file.txt
+		go-file-reader read --tail 20 file.log
+		go-file-reader read --pager big.log
+		go-file-reader read -f syntax -l main.go
+		go-file-reader read -f hex --offset 512 --length 256 image.bin
+		go-file-reader read app.log.gz
+		go-file-reader read --list logs.tar.gz
+		go-file-reader read logs.tar.gz:var/log/app.log
+		go-file-reader read -f table --columns status,request --sort -bytes access.log
+		go-file-reader read -f json --filter "level=error" --filter "duration>2" app.log`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: runRead,
 }
 
 func init() {
 	rootCmd.AddCommand(readCmd)
 
-	readCmd.Flags().StringVarP(&format, "format", "f", "plain", "output format (plain, json, table)")
+	readCmd.Flags().StringVarP(&format, "format", "f", "plain", "output format (plain, json, table, syntax, hex)")
 	readCmd.Flags().BoolVarP(&lineNumber, "lines", "l", false, "show line numbers")
 	readCmd.Flags().IntVarP(&maxLines, "max-lines", "n", 0, "maximum number of lines to read (0 for all)")
-	readCmd.Flags().StringVarP(&encoding, "encoding", "e", "utf-8", "file encoding")
+	readCmd.Flags().StringVarP(&encoding, "encoding", "e", "", "file encoding: auto, utf-8, utf-16le, utf-16be, latin1, windows-1252, shift_jis or another IANA name (default from config)")
+	readCmd.Flags().IntVar(&fromLine, "from-line", 0, "first line to read, starting at 1")
+	readCmd.Flags().IntVar(&toLine, "to-line", 0, "last line to read (0 for the end of the file)")
+	readCmd.Flags().Int64Var(&offset, "offset", 0, "byte offset to start reading at")
+	readCmd.Flags().Int64Var(&length, "length", 0, "number of bytes to read from the offset (0 for the rest of the file)")
+	readCmd.Flags().IntVarP(&tailLines, "tail", "t", 0, "read only the last N lines")
+	readCmd.Flags().BoolVarP(&usePager, "pager", "p", false, "page through the files interactively")
+	readCmd.Flags().StringVar(&parseAs, "parse", "auto", "parse lines as csv, tsv, jsonl, logfmt or apache for the table and json formats (auto detects, none keeps raw lines)")
+	readCmd.Flags().StringSliceVar(&columns, "columns", nil, "parsed columns to show, in order")
+	readCmd.Flags().StringSliceVar(&sortBy, "sort", nil, "parsed columns to sort by, prefix with - for descending")
+	readCmd.Flags().StringArrayVar(&filters, "filter", nil, "keep parsed rows matching column=value, !=, ~regex, !~, >, >=, < or <= (may be repeated)")
+	readCmd.Flags().BoolVarP(&listMembers, "list", "L", false, "list the members of zip and tar archives instead of reading them")
+	readCmd.Flags().StringVar(&readColor, "color", "auto", "color syntax and hex output (auto, always, never)")
 }
 
 func runRead(cmd *cobra.Command, args []string) error {
-	filePath := args[0]
+	files, err := expandPaths(args)
+	if err != nil {
+		return err
+	}
 
-	if err := validateFile(filePath); err != nil {
+	encoding, err := resolveEncoding(encoding)
+	if err != nil {
 		return err
 	}
 
-	fileReader := reader.New(&reader.Config{
-		FilePath:	filePath,
+	if listMembers {
+		return runList(files)
+	}
+
+	color, err := useColor(readColor)
+	if err != nil {
+		return err
+	}
+
+	readerConfig := reader.Config{
 		MaxLines:	maxLines,
 		ShowLines:	lineNumber,
 		Encoding:	encoding,
 		BufferSize:	cfg.Reader.BufferSize,
-	})
+		FromLine:	fromLine,
+		ToLine:		toLine,
+		Offset:		offset,
+		Length:		length,
+		Tail:		tailLines,
+	}
+	// hex dumps read bytes, and --max-lines counts rows of the dump
+	if format == "hex" {
+		if fromLine > 0 || toLine > 0 || tailLines > 0 {
+			return fmt.Errorf("--format hex takes --offset, --length and --max-lines, not line ranges")
+		}
+		readerConfig.Raw = true
+		if maxLines > 0 && (length == 0 || length > int64(maxLines)*16) {
+			readerConfig.Length = int64(maxLines) * 16
+		}
+	}
+	if err := readerConfig.Validate(); err != nil {
+		return err
+	}
 
-	content, err := fileReader.Read()
-	if err != nil {
-		log.Error("Failed to read file",
-			logger.String("file", filePath),
-			logger.Error(err))
-		return fmt.Errorf("failed to read file: %w", err)
+	// the pager only needs a terminal; piped output falls back to printing
+	if usePager && pager.IsTerminal(os.Stdout) {
+		return runPager(files, readerConfig)
 	}
 
-	fmt_handler, err := formatter.New(format, &formatter.Config{
+	fmtHandler, err := formatter.New(format, &formatter.Config{
 		ShowLineNumbers:	lineNumber,
 		MaxWidth:			cfg.Formatter.MaxWidth,
 		Theme:				cfg.Formatter.Theme,
+		ColorOutput:		color,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create formatter: %w", err)
 	}
 
-	output, err := fmt_handler.Format(content)
+	query, err := buildQuery()
+	if err != nil {
+		return err
+	}
+
+	contents, err := readFiles(files, readerConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := parseContents(contents, readerConfig, query); err != nil {
+		return err
+	}
+
+	return printContents(contents, fmtHandler, format)
+}
+
+func buildQuery() (*parser.Query, error) {
+	query := &parser.Query{Columns: columns, Sort: sortBy}
+	for _, expr := range filters {
+		filter, err := parser.ParseFilter(expr)
+		if err != nil {
+			return nil, err
+		}
+		query.Filters = append(query.Filters, filter)
+	}
+
+	querying := len(columns) > 0 || len(sortBy) > 0 || len(filters) > 0
+	if querying && (parseAs == "none" || (format != "table" && format != "json")) {
+		return nil, fmt.Errorf("--columns, --sort and --filter need parsed records: use --format table or json without --parse none")
+	}
+	if parseAs != "auto" && parseAs != "none" && !slices.Contains(parser.GetAvailableFormats(), parseAs) {
+		return nil, fmt.Errorf("unsupported parse format: %s", parseAs)
+	}
+
+	return query, nil
+}
+
+// parseContents turns the lines of structured files into records for the
+// table and json formats. Plain output always shows the raw lines.
+func parseContents(contents []*reader.Content, readerConfig reader.Config, query *parser.Query) error {
+	if parseAs == "none" || (format != "table" && format != "json") {
+		return nil
+	}
+
+	for _, content := range contents {
+		kind := parseAs
+		if kind == "auto" {
+			if kind = parser.Detect(content); kind == "" {
+				continue
+			}
+		}
+
+		header, err := readHeader(content, kind, readerConfig)
+		if err != nil {
+			return err
+		}
+
+		records, err := parser.Parse(content, kind, header)
+		if err != nil {
+			return err
+		}
+		if err := query.Apply(records); err != nil {
+			return fmt.Errorf("%s: %w", content.Metadata.FilePath, err)
+		}
+		content.Records = records
+	}
+
+	return nil
+}
+
+// readHeader fetches the header line of a CSV or TSV file when the lines
+// that were read start further down.
+func readHeader(content *reader.Content, kind string, readerConfig reader.Config) ([]string, error) {
+	if kind != parser.FormatCSV && kind != parser.FormatTSV {
+		return nil, nil
+	}
+	if len(content.Lines) > 0 && content.Lines[0].Number == 1 {
+		return nil, nil
+	}
+
+	headerConfig := reader.Config{
+		FilePath:	content.Metadata.FilePath,
+		Encoding:	readerConfig.Encoding,
+		BufferSize:	readerConfig.BufferSize,
+		ToLine:		1,
+	}
+	first, err := reader.New(&headerConfig).Read()
 	if err != nil {
-		return fmt.Errorf("failed to format content: %w", err)
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	if len(first.Lines) == 0 {
+		return nil, nil
+	}
+
+	return parser.ParseHeader(first.Lines[0].Content, kind)
+}
+
+// readFiles reads every file with the same settings. Everything read is held
+// in memory, so only reads with a fixed upper bound skip the size limit.
+func readFiles(files []string, readerConfig reader.Config) ([]*reader.Content, error) {
+	limited := !readerConfig.Bounded() && readerConfig.Search == nil
+	for _, filePath := range files {
+		if err := validatePath(filePath); err != nil {
+			return nil, err
+		}
+		if limited {
+			if err := validateSize(filePath); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// compressed files are small on disk, so the limit also applies to
+	// what they decompress to
+	if limited {
+		readerConfig.MaxSize = int64(cfg.Reader.MaxFileSize)
+	}
+
+	var contents []*reader.Content
+	for _, filePath := range files {
+		fileConfig := readerConfig
+		fileConfig.FilePath = filePath
+
+		content, err := reader.New(&fileConfig).Read()
+		if err != nil {
+			log.Error("Failed to read file",
+				logger.String("file", filePath),
+				logger.Error(err))
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		contents = append(contents, content)
 	}
 
-	fmt.Println(output)
+	return contents, nil
+}
+
+// printContents writes each file in turn, or all of them at once for
+// formatters that combine files.
+func printContents(contents []*reader.Content, fmtHandler formatter.Formatter, format string) error {
+	if multi, ok := fmtHandler.(formatter.MultiFormatter); ok && len(contents) > 1 {
+		output, err := multi.FormatAll(contents)
+		if err != nil {
+			return fmt.Errorf("failed to format content: %w", err)
+		}
+		fmt.Println(output)
+	} else {
+		for i, content := range contents {
+			output, err := fmtHandler.Format(content)
+			if err != nil {
+				return fmt.Errorf("failed to format content: %w", err)
+			}
 
-	log.Info("File read successfully",
- 		logger.String("file", filePath),
-		logger.Int("lines", len(content.Lines)),
-		logger.String("format", format))
+			if len(contents) > 1 {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("==> %s <==\n", content.Metadata.FilePath)
+			}
+			fmt.Println(output)
+		}
+	}
+
+	for _, content := range contents {
+		log.Info("File read successfully",
+			logger.String("file", content.Metadata.FilePath),
+			logger.Int("lines", len(content.Lines)),
+			logger.String("format", format))
+	}
 
 	return nil
 }
 
-func validateFile(filePath string) error {
+// runList shows the members of each archive as lines for plain output and
+// as records for the table and json formats.
+func runList(files []string) error {
+	fmtHandler, err := formatter.New(format, &formatter.Config{
+		ShowLineNumbers:	lineNumber,
+		MaxWidth:			cfg.Formatter.MaxWidth,
+		Theme:				cfg.Formatter.Theme,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create formatter: %w", err)
+	}
+
+	var contents []*reader.Content
+	for _, filePath := range files {
+		if err := validatePath(filePath); err != nil {
+			return err
+		}
+
+		startTime := time.Now()
+		members, err := reader.ListArchive(filePath)
+		if err != nil {
+			return err
+		}
+
+		meta, err := reader.GetFileInfo(filePath)
+		if err != nil {
+			return err
+		}
+		if kind, _ := reader.DetectCompression(filePath); kind == reader.ArchiveZip {
+			meta.Archive = reader.ArchiveZip
+		} else {
+			meta.Archive = reader.ArchiveTar
+			meta.Compression = kind
+		}
+
+		content := &reader.Content{
+			Records: &reader.Records{
+				Format:		"archive",
+				Columns:	[]string{"name", "size", "compressed_size", "modified"},
+			},
+		}
+		for i, member := range members {
+			name := member.Name
+			if member.Dir {
+				name = strings.TrimSuffix(name, "/") + "/"
+			}
+			content.Lines = append(content.Lines, reader.Line{
+				Number:		i + 1,
+				Content:	fmt.Sprintf("%10d  %s  %s", member.Size, member.ModTime.Format("2006-01-02 15:04:05"), name),
+			})
+
+			fields := map[string]any{
+				"name":		name,
+				"size":		json.Number(strconv.FormatInt(member.Size, 10)),
+				"modified":	member.ModTime.Format(time.RFC3339),
+			}
+			if member.CompressedSize > 0 {
+				fields["compressed_size"] = json.Number(strconv.FormatInt(member.CompressedSize, 10))
+			}
+			content.Records.Rows = append(content.Records.Rows, reader.Record{Line: i + 1, Fields: fields})
+		}
+
+		// member names are listed as UTF-8 whatever the archive used
+		meta.Encoding = "utf-8"
+		meta.LineCount = len(content.Lines)
+		meta.EndOffset = meta.Size
+		meta.ReadTime = time.Since(startTime)
+		content.Metadata = *meta
+		contents = append(contents, content)
+	}
+
+	return printContents(contents, fmtHandler, format)
+}
+
+func runPager(files []string, readerConfig reader.Config) error {
+	if readerConfig.Offset > 0 || readerConfig.Length > 0 || readerConfig.ToLine > 0 {
+		return fmt.Errorf("--pager only supports --from-line and --tail")
+	}
+
+	for _, filePath := range files {
+		if err := validatePath(filePath); err != nil {
+			return err
+		}
+		// the pager seeks around the file, which compressed data cannot do
+		archivePath, member := reader.SplitArchivePath(filePath)
+		if kind, err := reader.DetectCompression(archivePath); err != nil || kind != "" || member != "" {
+			return fmt.Errorf("--pager cannot page compressed or archived files: %s", filePath)
+		}
+	}
+
+	return pager.New(files, pager.Options{
+		ShowLineNumbers:	lineNumber,
+		StartLine:			readerConfig.FromLine,
+		StartAtEnd:			readerConfig.Tail > 0,
+		BufferSize:			readerConfig.BufferSize,
+		Encoding:			readerConfig.Encoding,
+	}).Run()
+}
+
+// resolveEncoding falls back to the configured encoding and checks that a
+// named one is supported before any file is opened.
+func resolveEncoding(name string) (string, error) {
+	if name == "" {
+		name = cfg.Reader.Encoding
+	}
+	if name != "auto" {
+		if _, err := reader.LookupCharset(name); err != nil {
+			return "", err
+		}
+	}
+	return name, nil
+}
+
+// expandPaths resolves glob patterns to the files they match. Plain paths are
+// kept as they are so a missing file is still reported by name.
+func expandPaths(args []string) ([]string, error) {
+	var files []string
+
+	for _, arg := range args {
+		if !strings.ContainsAny(arg, "*?[") {
+			files = append(files, arg)
+			continue
+		}
+
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", arg, err)
+		}
+
+		found := false
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && !info.IsDir() {
+				files = append(files, match)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no files match %s", arg)
+		}
+	}
+
+	return files, nil
+}
+
+// validatePath checks the file on disk; for archive:member paths that is
+// the archive, and the member itself is looked up when it is read.
+func validatePath(filePath string) error {
+	filePath, _ = reader.SplitArchivePath(filePath)
 	info, err := os.Stat(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -98,6 +474,17 @@ func validateFile(filePath string) error {
 	if info.IsDir() {
 		return fmt.Errorf("path is a directory, not a file: %s", filePath)
 	}
+
+	return nil
+}
+
+func validateSize(filePath string) error {
+	filePath, _ = reader.SplitArchivePath(filePath)
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("cannot access file: %w", err)
+	}
+
 	if info.Size() > int64(cfg.Reader.MaxFileSize) {
 		return fmt.Errorf("file too large: %d bytes (max: %d)", info.Size(), cfg.Reader.MaxFileSize)
 	}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/samnart1/GoLang/006reader/internal/logger"
+	"github.com/samnart1/GoLang/006reader/internal/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveRoot		string
+	serveAddress	string
+	serveExts		[]string
+)
+
+var serveCmd = &cobra.Command{
+	Use: "serve",
+	Short: "Serve a directory over HTTP",
+	Long: `Serve the files under a directory over HTTP. Every path is checked by the
+	file validator and nothing outside the root can be reached, including
+	through symlinks
+
+	Endpoints:
+		GET /api/files?path=dir          list a directory
+		GET /api/read?path=file          read a file; takes format, lines, color, max_lines,
+		                                 from_line, to_line, offset, length, tail, encoding;
+		                                 format=hex also shows binary files
+		GET /api/raw?path=file           the file's bytes, with Range request support
+		GET /api/watch?path=file         stream watch events as Server-Sent Events, or
+		                                 over a WebSocket when upgraded; takes tail, poll
+
+	Examples:
+		go-file-reader serve --root /var/log
+		go-file-reader serve --root ./logs --addr 127.0.0.1:9000
+		curl "localhost:8090/api/read?path=app.log&tail=20&format=json"
+		curl -H "Range: bytes=0-1023" "localhost:8090/api/raw?path=app.log"
+		curl -N "localhost:8090/api/watch?path=app.log&tail=true"`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVarP(&serveRoot, "root", "r", "", "directory to serve (default from config)")
+	serveCmd.Flags().StringVarP(&serveAddress, "addr", "a", "", "address to listen on (default from config)")
+	serveCmd.Flags().StringSliceVar(&serveExts, "extensions", nil, "file extensions that may be served, replacing the validator's list")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	serverConfig := server.Config{
+		Root:			cfg.Server.Root,
+		Address:		cfg.Server.Address,
+		BufferSize:		cfg.Reader.BufferSize,
+		MaxFileSize:	cfg.Reader.MaxFileSize,
+		Encoding:		cfg.Reader.Encoding,
+		MaxWidth:		cfg.Formatter.MaxWidth,
+		Theme:			cfg.Formatter.Theme,
+		Extensions:		cfg.Server.Extensions,
+		PollInterval:	1000,
+	}
+	if serveRoot != "" {
+		serverConfig.Root = serveRoot
+	}
+	if serveAddress != "" {
+		serverConfig.Address = serveAddress
+	}
+	if len(serveExts) > 0 {
+		serverConfig.Extensions = serveExts
+	}
+
+	srv, err := server.New(serverConfig, log)
+	if err != nil {
+		return err
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- srv.Start()
+	}()
+
+	fmt.Printf("Serving %s on %s (Press Ctrl+C to stop)\n", srv.Root(), serverConfig.Address)
+
+	signChan := make(chan os.Signal, 1)
+	signal.Notify(signChan, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-signChan:
+		fmt.Println("\nShutting down...")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error("Shutdown failed", logger.Error(err))
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+	log.Info("Server stopped")
+	return nil
+}
\ No newline at end of file
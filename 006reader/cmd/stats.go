@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/samnart1/GoLang/006reader/internal/formatter"
+	"github.com/samnart1/GoLang/006reader/internal/logger"
+	"github.com/samnart1/GoLang/006reader/internal/reader"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsFormat		string
+	statsEncoding	string
+	topCount		int
+)
+
+var statsCmd = &cobra.Command{
+	Use: "stats [file|glob]...",
+	Short: "Show line, word and character statistics for files",
+	Long: `Count the lines, words and characters of files and report the longest line,
+	the line ending style, lines with trailing whitespace, the encoding and the
+	most repeated lines. Files are streamed, so size is no limit
+
+	Examples:
+		go-file-reader stats app.log
+		go-file-reader stats --top 5 "logs/*.log"
+		go-file-reader stats -f table a.csv b.csv
+		go-file-reader stats -f json app.log.gz`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runStats,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringVarP(&statsFormat, "format", "f", "plain", "output format (plain, json, table)")
+	statsCmd.Flags().StringVarP(&statsEncoding, "encoding", "e", "", "file encoding (default from config)")
+	statsCmd.Flags().IntVar(&topCount, "top", 10, "number of most repeated lines to show (0 for none)")
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	files, err := expandPaths(args)
+	if err != nil {
+		return err
+	}
+
+	fileEncoding, err := resolveEncoding(statsEncoding)
+	if err != nil {
+		return err
+	}
+
+	fmtHandler, err := formatter.New(statsFormat, &formatter.Config{
+		MaxWidth:	cfg.Formatter.MaxWidth,
+		Theme:		cfg.Formatter.Theme,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create formatter: %w", err)
+	}
+	statsFormatter, ok := fmtHandler.(formatter.StatsFormatter)
+	if !ok {
+		return fmt.Errorf("the %s format cannot show statistics", statsFormat)
+	}
+
+	for _, filePath := range files {
+		if err := validatePath(filePath); err != nil {
+			return err
+		}
+	}
+
+	var stats []*reader.Stats
+	for _, filePath := range files {
+		fileStats, err := reader.New(&reader.Config{
+			FilePath:	filePath,
+			Encoding:	fileEncoding,
+			BufferSize:	cfg.Reader.BufferSize,
+		}).Stats(topCount)
+		if err != nil {
+			log.Error("Failed to read file",
+				logger.String("file", filePath),
+				logger.Error(err))
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+		stats = append(stats, fileStats)
+	}
+
+	output, err := statsFormatter.FormatStats(stats)
+	if err != nil {
+		return fmt.Errorf("failed to format statistics: %w", err)
+	}
+	fmt.Println(output)
+
+	return nil
+}
\ No newline at end of file
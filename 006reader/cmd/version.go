@@ -32,7 +32,7 @@ func runVersion(cmd *cobra.Command, args []string) {
 	fmt.Printf("Commit:		%s\n", info.Commit)
 	fmt.Printf("Build Date:	%s\n", info.Date)
 	fmt.Printf("Go Version:	%s\n", info.GoVersion)
-	fmt.Printf("Platform:	%s\n", info.OS, info.Arch)
+	fmt.Printf("Platform:	%s/%s\n", info.OS, info.Arch)
 }
 
 func SetVersion(v, c, d string) {
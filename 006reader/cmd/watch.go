@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/samnart1/GoLang/006reader/internal/logger"
@@ -12,20 +13,32 @@ import (
 )
 
 var (
-	tailMode bool
-	interval int
+	tailMode		bool
+	interval		int
+	forcePoll		bool
+	finishRotated	bool
+	watchEncoding	string
+	watchColor		string
 )
 
+// fileColors tell the files of a multi-file watch apart
+var fileColors = []string{"36", "33", "32", "35", "34", "96", "93", "92", "95", "94"}
+
 var watchCmd = &cobra.Command{
-	Use: "watch [file]",
-	Short: "Watch file for changes and display updates",
-	Long: `Watch a file for changes and display updates in real-time
+	Use: "watch [file|dir|glob]...",
+	Short: "Watch files for changes and display updates",
+	Long: `Watch files for changes and display updates in real-time. Directories are
+	watched recursively and new files matching a directory or glob are picked up
+	as they appear; ** in a glob matches any number of directories
 	
 	Examples:
 		go-file-reader watch file.txt
 		go-file-reader watch --tail file.log
-		go-file-reader watch --interval 500 file.txt`,
-	Args: cobra.ExactArgs(1),
+		go-file-reader watch --interval 500 --poll file.txt
+		go-file-reader watch --tail --finish-rotated /var/log/app.log
+		go-file-reader watch --tail "/var/log/app/*.log"
+		go-file-reader watch --tail /var/log/app "/srv/**/*.log"`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: runWatch,
 }
 
@@ -34,37 +47,74 @@ func init() {
 
 	watchCmd.Flags().BoolVarP(&tailMode, "tail", "t", false, "tail mode (show only new content)")
 	watchCmd.Flags().IntVarP(&interval, "interval", "i", 1000, "polling interval in milliseconds")
+	watchCmd.Flags().BoolVar(&forcePoll, "poll", false, "poll the file instead of using file system notifications")
+	watchCmd.Flags().BoolVar(&finishRotated, "finish-rotated", false, "read the rest of a rotated file before following the new one")
+	watchCmd.Flags().StringVarP(&watchEncoding, "encoding", "e", "", "file encoding (default from config)")
+	watchCmd.Flags().StringVar(&watchColor, "color", "auto", "color file names (auto, always, never)")
 }
 
 func runWatch(cmd *cobra.Command, args []string) error {
-	filePath := args[0]
+	fileEncoding, err := resolveEncoding(watchEncoding)
+	if err != nil {
+		return err
+	}
 
-	if err := validateFile(filePath); err != nil {
+	color, err := useColor(watchColor)
+	if err != nil {
 		return err
 	}
 
-	watcher := reader.NewWatcher(&reader.WatchConfig{
-		FilePath:		filePath,
+	// only appended bytes are read, so large files are fine
+	watcher := reader.NewMultiWatcher(&reader.MultiWatchConfig{
+		Paths:			args,
 		TailMode:		tailMode,
 		PollInterval:	interval,
 		BufferSize:		cfg.Reader.BufferSize,
+		Encoding:		fileEncoding,
+		Poll:			forcePoll,
+		FinishRotated:	finishRotated,
 	})
 
 	signChan := make(chan os.Signal, 1)
 	signal.Notify(signChan, syscall.SIGINT, syscall.SIGTERM)
 
-	fmt.Println("Watching file: %s (Press Ctrl+C to stop)\n", filePath)
-	fmt.Println("------------------------------------------")
-
 	if err := watcher.Start(); err != nil {
 		return fmt.Errorf("failed to start watcher: %w", err)
 	} 
 	defer watcher.Stop()
 
+	// name the source of each line unless a single file was asked for
+	multiple := len(args) > 1 || len(watcher.Files()) != 1
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err != nil || info.IsDir() {
+			multiple = true
+		}
+	}
+
+	fmt.Printf("Watching %s (Press Ctrl+C to stop)\n", strings.Join(args, ", "))
+	if multiple {
+		fmt.Printf("Following %d files\n", len(watcher.Files()))
+	}
+	if watcher.Polling() {
+		fmt.Printf("Polling every %dms\n", interval)
+	}
+	fmt.Println("------------------------------------------")
+
+	colors := make(map[string]string)
 	for {
 		select {
 		case event := <-watcher.Events():
-			fmt.Printf("[%s] %s\n", event.Time.Format("15:04:05"), event.Content)
+			prefix := ""
+			if multiple {
+				prefix = filePrefix(event.File, colors, color)
+			}
+
+			switch event.Type {
+			case "initial", "new_line":
+				fmt.Printf("[%s] %s%s\n", event.Time.Format("15:04:05"), prefix, event.Content)
+			default:
+				fmt.Printf("[%s] %s*** %s ***\n", event.Time.Format("15:04:05"), prefix, event.Content)
+			}
 
 		case err := <-watcher.Errors():
 			log.Error("Watcher error", logger.Error(err))
@@ -75,4 +125,19 @@ func runWatch(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 	}
+}
+
+// filePrefix labels a line with its file, giving each file the next color
+// the first time it is seen.
+func filePrefix(file string, colors map[string]string, color bool) string {
+	if !color {
+		return file + " | "
+	}
+
+	code, ok := colors[file]
+	if !ok {
+		code = fileColors[len(colors)%len(fileColors)]
+		colors[file] = code
+	}
+	return fmt.Sprintf("\x1b[%sm%s\x1b[0m | ", code, file)
 }
\ No newline at end of file
@@ -10,6 +10,7 @@ type Config struct {
 	Reader		ReaderConfig	`mapstructure:"reader"`
 	Formatter	FormatterConfig	`mapstructure:"formatter"`
 	Logger		LoggerConfig	`mapstructure:"logger"`
+	Server		ServerConfig	`mapstructure:"server"`
 }
 
 type ReaderConfig struct {
@@ -29,6 +30,12 @@ type LoggerConfig struct {
 	Output	string	`mapstructure:"output"`
 }
 
+type ServerConfig struct {
+	Address		string		`mapstructure:"address"`
+	Root		string		`mapstructure:"root"`
+	Extensions	[]string	`mapstructure:"extensions"`
+}
+
 func Load() (*Config, error) {
 
 	SetDefaults()
@@ -48,7 +55,7 @@ func Load() (*Config, error) {
 func SetDefaults() {
 	viper.SetDefault("reader.buffer_size", 8192)
 	viper.SetDefault("reader.max_file_size", 100*1024*1024)
-	viper.SetDefault("reader.encoding", "utf-8")
+	viper.SetDefault("reader.encoding", "auto")
 
 	viper.SetDefault("formatter.max_width", 120)
 	viper.SetDefault("formatter.theme", "default")
@@ -56,6 +63,10 @@ func SetDefaults() {
 	viper.SetDefault("logger.level", "info")
 	viper.SetDefault("logger.format", "json")
 	viper.SetDefault("logger.output", "stdout")
+
+	// only local clients by default, since nothing is authenticated
+	viper.SetDefault("server.address", "127.0.0.1:8090")
+	viper.SetDefault("server.root", ".")
 }
 
 func validate(config *Config) error {
@@ -0,0 +1,305 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	OpEqual		= "equal"
+	OpDelete	= "delete"
+	OpInsert	= "insert"
+)
+
+// Diff is the difference between two files as unified diff hunks.
+type Diff struct {
+	OldPath		string		`json:"old_path"`
+	NewPath		string		`json:"new_path"`
+	OldTime		time.Time	`json:"old_time"`
+	NewTime		time.Time	`json:"new_time"`
+	Added		int			`json:"added"`
+	Removed		int			`json:"removed"`
+	Hunks		[]Hunk		`json:"hunks"`
+}
+
+// Hunk is a run of changes with the unchanged lines around them. Starts are
+// 1-based, as in the "@@ -start,lines +start,lines @@" header.
+type Hunk struct {
+	OldStart	int		`json:"old_start"`
+	OldLines	int		`json:"old_lines"`
+	NewStart	int		`json:"new_start"`
+	NewLines	int		`json:"new_lines"`
+	Lines		[]Line	`json:"lines"`
+}
+
+// Line is one line of a hunk. Deleted lines have no new number and inserted
+// lines no old one.
+type Line struct {
+	Op			string	`json:"op"`
+	OldNumber	int		`json:"old_number,omitempty"`
+	NewNumber	int		`json:"new_number,omitempty"`
+	Content		string	`json:"content"`
+	// NoNewline marks the last line of a file that does not end in a newline
+	NoNewline	bool	`json:"no_newline,omitempty"`
+}
+
+// Text is a file split into lines.
+type Text struct {
+	Lines			[]string
+	// NoFinalNewline is set when the last line has no line ending
+	NoFinalNewline	bool
+}
+
+type Options struct {
+	// Context is the number of unchanged lines shown around each change
+	Context				int
+	IgnoreWhitespace	bool
+	IgnoreCase			bool
+}
+
+// Compare diffs two files. The paths and times are left for the caller to
+// fill in.
+func Compare(oldText, newText Text, options Options) *Diff {
+	oldKeys, newKeys := keys(oldText, options), keys(newText, options)
+
+	d := &Diff{Hunks: hunks(editScript(oldKeys, newKeys), oldText, newText, options.Context)}
+	for _, hunk := range d.Hunks {
+		for _, line := range hunk.Lines {
+			switch line.Op {
+			case OpInsert:
+				d.Added++
+			case OpDelete:
+				d.Removed++
+			}
+		}
+	}
+	return d
+}
+
+// Equal reports whether there are no differences.
+func (d *Diff) Equal() bool {
+	return len(d.Hunks) == 0
+}
+
+// Header is the hunk's "@@ -1,4 +1,5 @@" line.
+func (h Hunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", span(h.OldStart, h.OldLines), span(h.NewStart, h.NewLines))
+}
+
+// span follows diff -u: a count of one is left out, and an empty side
+// starts at the line before it
+func span(start, lines int) string {
+	switch lines {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, lines)
+}
+
+// noNewlineKey is appended to the key of a last line without a newline, so
+// it differs from the same text followed by one.
+const noNewlineKey = "\x00no newline"
+
+// keys are what lines are compared by once the ignore options are applied.
+// Ignoring whitespace also ignores a missing final newline.
+func keys(text Text, options Options) []string {
+	markEnd := text.NoFinalNewline && len(text.Lines) > 0 && !options.IgnoreWhitespace
+	if !options.IgnoreWhitespace && !options.IgnoreCase && !markEnd {
+		return text.Lines
+	}
+
+	result := make([]string, len(text.Lines))
+	for i, line := range text.Lines {
+		if options.IgnoreWhitespace {
+			line = strings.Join(strings.Fields(line), " ")
+		}
+		if options.IgnoreCase {
+			line = strings.ToLower(line)
+		}
+		result[i] = line
+	}
+	if markEnd {
+		result[len(result)-1] += noNewlineKey
+	}
+	return result
+}
+
+// edit is one step of an edit script: keep, delete or insert a line, where
+// old and new index the line in each file.
+type edit struct {
+	op			string
+	old, new	int
+}
+
+// editScript finds a shortest edit script with Myers' algorithm. The common
+// prefix and suffix are trimmed first, so the search only spans the part
+// that changed.
+func editScript(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var script []edit
+	for i := 0; i < prefix; i++ {
+		script = append(script, edit{OpEqual, i, i})
+	}
+
+	for _, e := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		script = append(script, edit{e.op, e.old + prefix, e.new + prefix})
+	}
+
+	for i := suffix; i > 0; i-- {
+		script = append(script, edit{OpEqual, len(a) - i, len(b) - i})
+	}
+	return script
+}
+
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	// v[k+max] is the furthest x reached on diagonal k; trace keeps v as it
+	// was before each round so the path can be walked back
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v[max-d:max+d+2]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[k-1+max] < v[k+1+max] {
+				x = v[k+1+max]
+			} else {
+				x = v[k-1+max] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k+max] = x
+
+			if x >= n && y >= m {
+				return backtrack(trace, n, m)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack walks the rounds of myers backwards from the end of both files
+// and returns the edits in order.
+func backtrack(trace [][]int, n, m int) []edit {
+	var script []edit
+	x, y := n, m
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		// trace[d] holds diagonals -d-1 .. d+1 of round d
+		at := func(k int) int { return trace[d][k+d] }
+
+		k := x - y
+		var prevK int
+		if k == -d || k != d && at(k-1) < at(k+1) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			script = append(script, edit{OpEqual, x, y})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			y--
+			script = append(script, edit{OpInsert, x, y})
+		} else {
+			x--
+			script = append(script, edit{OpDelete, x, y})
+		}
+	}
+
+	for i, j := 0, len(script)-1; i < j; i, j = i+1, j-1 {
+		script[i], script[j] = script[j], script[i]
+	}
+	return script
+}
+
+// hunks groups the changes of script with up to context unchanged lines on
+// either side, merging changes whose context would overlap.
+func hunks(script []edit, oldText, newText Text, context int) []Hunk {
+	result := []Hunk{}
+
+	for i := 0; i < len(script); {
+		if script[i].op == OpEqual {
+			i++
+			continue
+		}
+
+		start := max(i-context, 0)
+		end := i
+		for end < len(script) {
+			if script[end].op != OpEqual {
+				end++
+				continue
+			}
+			// stop once the unchanged run is too long to bridge
+			run := end
+			for run < len(script) && script[run].op == OpEqual {
+				run++
+			}
+			if run == len(script) || run-end > 2*context {
+				end = min(end+context, len(script))
+				break
+			}
+			end = run
+		}
+
+		result = append(result, hunk(script[start:end], oldText, newText))
+		i = end
+	}
+
+	return result
+}
+
+func hunk(script []edit, oldText, newText Text) Hunk {
+	h := Hunk{OldStart: script[0].old + 1, NewStart: script[0].new + 1}
+
+	for _, e := range script {
+		switch e.op {
+		case OpEqual:
+			h.Lines = append(h.Lines, Line{Op: OpEqual, OldNumber: e.old + 1, NewNumber: e.new + 1, Content: oldText.Lines[e.old], NoNewline: oldText.lastWithoutNewline(e.old)})
+			h.OldLines++
+			h.NewLines++
+		case OpDelete:
+			h.Lines = append(h.Lines, Line{Op: OpDelete, OldNumber: e.old + 1, Content: oldText.Lines[e.old], NoNewline: oldText.lastWithoutNewline(e.old)})
+			h.OldLines++
+		case OpInsert:
+			h.Lines = append(h.Lines, Line{Op: OpInsert, NewNumber: e.new + 1, Content: newText.Lines[e.new], NoNewline: newText.lastWithoutNewline(e.new)})
+			h.NewLines++
+		}
+	}
+
+	return h
+}
+
+func (t Text) lastWithoutNewline(i int) bool {
+	return t.NoFinalNewline && i == len(t.Lines)-1
+}
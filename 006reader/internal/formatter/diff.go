@@ -0,0 +1,115 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samnart1/GoLang/006reader/internal/diff"
+)
+
+const (
+	diffHeaderColor	= "\x1b[1m"
+	diffHunkColor	= "\x1b[36m"
+	diffDeleteColor	= "\x1b[31m"
+	diffInsertColor	= "\x1b[32m"
+)
+
+// noNewlineMarker follows a line that ends its file without a newline.
+const noNewlineMarker = `\ No newline at end of file`
+
+var diffMarks = map[string]string{
+	diff.OpEqual:	" ",
+	diff.OpDelete:	"-",
+	diff.OpInsert:	"+",
+}
+
+// FormatDiff writes a unified diff like diff -u, colored when ColorOutput
+// is set.
+func (f *PlainFormatter) FormatDiff(d *diff.Diff) (string, error) {
+	var builder strings.Builder
+	if d.Equal() {
+		return "", nil
+	}
+
+	paint := func(code, text string) string {
+		if !f.config.ColorOutput {
+			return text
+		}
+		return code + text + resetColor
+	}
+
+	builder.WriteString(paint(diffHeaderColor, fmt.Sprintf("--- %s\t%s", d.OldPath, d.OldTime.Format("2006-01-02 15:04:05.000000000 -0700"))) + "\n")
+	builder.WriteString(paint(diffHeaderColor, fmt.Sprintf("+++ %s\t%s", d.NewPath, d.NewTime.Format("2006-01-02 15:04:05.000000000 -0700"))) + "\n")
+
+	for _, hunk := range d.Hunks {
+		builder.WriteString(paint(diffHunkColor, hunk.Header()) + "\n")
+		for _, line := range hunk.Lines {
+			text := diffMarks[line.Op] + line.Content
+			switch line.Op {
+			case diff.OpDelete:
+				text = paint(diffDeleteColor, text)
+			case diff.OpInsert:
+				text = paint(diffInsertColor, text)
+			}
+			builder.WriteString(text + "\n")
+			if line.NoNewline {
+				builder.WriteString(noNewlineMarker + "\n")
+			}
+		}
+	}
+
+	return strings.TrimSuffix(builder.String(), "\n"), nil
+}
+
+func (f *JSONFormatter) FormatDiff(d *diff.Diff) (string, error) {
+	output := struct {
+		*diff.Diff
+		FormatterInfo	map[string]interface{}	`json:"formatter_info"`
+	}{
+		Diff: d,
+		FormatterInfo: f.info(),
+	}
+
+	jsonBytes, err := json.MarshalIndent(output, "", " ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal to JSON: %w", err)
+	}
+	return string(jsonBytes), nil
+}
+
+// FormatDiff shows each line of the hunks with its number in both files.
+func (f *TableFormatter) FormatDiff(d *diff.Diff) (string, error) {
+	var builder strings.Builder
+
+	if !d.Equal() {
+		headers := []string{"Old", "New", "", "Content"}
+		var rows [][]string
+		for i, hunk := range d.Hunks {
+			if i > 0 {
+				rows = append(rows, []string{"", "", "", "..."})
+			}
+			for _, line := range hunk.Lines {
+				rows = append(rows, []string{number(line.OldNumber), number(line.NewNumber), diffMarks[line.Op], line.Content})
+				if line.NoNewline {
+					rows = append(rows, []string{"", "", "", noNewlineMarker})
+				}
+			}
+		}
+		f.writeTable(&builder, headers, rows, []bool{true, true, false, false})
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString(fmt.Sprintf("Summary: %s -> %s, %d hunks, %d added, %d removed\n",
+		d.OldPath, d.NewPath, len(d.Hunks), d.Added, d.Removed))
+
+	return strings.TrimSuffix(builder.String(), "\n"), nil
+}
+
+func number(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
\ No newline at end of file
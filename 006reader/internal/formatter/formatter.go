@@ -2,7 +2,9 @@ package formatter
 
 import (
 	"fmt"
+	"strconv"
 
+	"github.com/samnart1/GoLang/006reader/internal/diff"
 	"github.com/samnart1/GoLang/006reader/internal/reader"
 )
 
@@ -11,6 +13,22 @@ type Formatter interface {
 	Name() string
 }
 
+// MultiFormatter is implemented by formatters that combine several files
+// into one document rather than printing them one after another.
+type MultiFormatter interface {
+	FormatAll(contents []*reader.Content) (string, error)
+}
+
+// StatsFormatter is implemented by formatters that can show file statistics.
+type StatsFormatter interface {
+	FormatStats(stats []*reader.Stats) (string, error)
+}
+
+// DiffFormatter is implemented by formatters that can show a diff.
+type DiffFormatter interface {
+	FormatDiff(d *diff.Diff) (string, error)
+}
+
 type Config struct {
 	ShowLineNumbers bool
 	MaxWidth		int
@@ -34,11 +52,34 @@ func New(format string, config *Config) (Formatter, error) {
 		return NewJSONFormatter(config), nil
 	case "table":
 		return NewTableFormatter(config), nil
+	case "syntax":
+		return NewSyntaxFormatter(config), nil
+	case "hex":
+		return NewHexFormatter(config), nil
 	default:
 		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
 }
 
 func GetAvailableFormats() []string {
-	return []string{"plain", "json", "table"}
+	return []string{"plain", "json", "table", "syntax", "hex"}
+}
+
+// lineLabel is the line number, or the byte offset prefixed with @ when the
+// read started mid-file and line numbers are unknown.
+func lineLabel(line reader.Line) string {
+	if line.Number > 0 {
+		return strconv.Itoa(line.Number)
+	}
+	return "@" + strconv.FormatInt(line.Offset, 10)
+}
+
+func labelWidth(lines []reader.Line) int {
+	width := 1
+	for _, line := range lines {
+		if n := len(lineLabel(line)); n > width {
+			width = n
+		}
+	}
+	return width
 }
\ No newline at end of file
@@ -0,0 +1,114 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/samnart1/GoLang/006reader/internal/reader"
+)
+
+const (
+	bytesPerRow	= 16
+	offsetColor	= "\x1b[36m"
+	zeroColor	= "\x1b[90m"
+	binaryColor	= "\x1b[33m"
+	resetColor	= "\x1b[0m"
+)
+
+// HexFormatter dumps raw bytes like hexdump -C: the offset, sixteen bytes in
+// hex and the same bytes as ASCII. Runs of identical rows collapse into "*".
+// It needs the content to have been read in raw mode.
+type HexFormatter struct {
+	config *Config
+}
+
+func NewHexFormatter(config *Config) *HexFormatter {
+	return &HexFormatter{config: config}
+}
+
+func (f *HexFormatter) Format(content *reader.Content) (string, error) {
+	var builder strings.Builder
+	meta := content.Metadata
+
+	if f.config.Theme != "minimal" {
+		builder.WriteString(fmt.Sprintf("File: %s (%d bytes", meta.FilePath, meta.DataSize()))
+		if meta.Compression != "" {
+			builder.WriteString(fmt.Sprintf(", %s", meta.Compression))
+		}
+		builder.WriteString(fmt.Sprintf("), bytes %d-%d\n\n", meta.StartOffset, meta.EndOffset))
+	}
+
+	data := content.Data
+	var previous []byte
+	squeezed := false
+
+	for pos := 0; pos < len(data); pos += bytesPerRow {
+		row := data[pos:min(pos+bytesPerRow, len(data))]
+
+		// like hexdump, repeats are skipped but the last row is always shown
+		if previous != nil && bytes.Equal(row, previous) && pos+bytesPerRow < len(data) {
+			if !squeezed {
+				builder.WriteString("*\n")
+				squeezed = true
+			}
+			continue
+		}
+		previous, squeezed = row, false
+
+		f.writeRow(&builder, meta.StartOffset+int64(pos), row)
+	}
+	builder.WriteString(fmt.Sprintf("%08x\n", meta.EndOffset))
+
+	return builder.String(), nil
+}
+
+func (f *HexFormatter) Name() string {
+	return "HexFormatter"
+}
+
+func (f *HexFormatter) writeRow(builder *strings.Builder, offset int64, row []byte) {
+	builder.WriteString(f.color(offsetColor, fmt.Sprintf("%08x", offset)))
+	builder.WriteString("  ")
+
+	for i := 0; i < bytesPerRow; i++ {
+		if i == bytesPerRow/2 {
+			builder.WriteString(" ")
+		}
+		if i >= len(row) {
+			builder.WriteString("   ")
+			continue
+		}
+		builder.WriteString(f.color(byteColor(row[i]), fmt.Sprintf("%02x", row[i])))
+		builder.WriteString(" ")
+	}
+
+	builder.WriteString(" |")
+	for _, b := range row {
+		char := "."
+		if b >= 0x20 && b < 0x7f {
+			char = string(rune(b))
+		}
+		builder.WriteString(f.color(byteColor(b), char))
+	}
+	builder.WriteString("|\n")
+}
+
+func (f *HexFormatter) color(code, text string) string {
+	if !f.config.ColorOutput || code == "" {
+		return text
+	}
+	return code + text + resetColor
+}
+
+// byteColor sets zero bytes and bytes outside printable ASCII apart.
+func byteColor(b byte) string {
+	switch {
+	case b == 0:
+		return zeroColor
+	case b < 0x20 || b >= 0x7f:
+		return binaryColor
+	default:
+		return ""
+	}
+}
\ No newline at end of file
@@ -0,0 +1,50 @@
+package formatter
+
+import (
+	"strings"
+
+	"github.com/samnart1/GoLang/006reader/internal/reader"
+)
+
+const (
+	highlightStart	= "\x1b[1;31m"
+	highlightEnd	= "\x1b[0m"
+)
+
+// highlight wraps the matched spans of text in color codes. Spans running
+// past the end of text, as happens after truncation, are clipped.
+func highlight(text string, spans []reader.Span) string {
+	var builder strings.Builder
+	pos := 0
+
+	for _, span := range spans {
+		start, end := span.Start, span.End
+		if end > len(text) {
+			end = len(text)
+		}
+		if start < pos || start >= end {
+			continue
+		}
+
+		builder.WriteString(text[pos:start])
+		builder.WriteString(highlightStart)
+		builder.WriteString(text[start:end])
+		builder.WriteString(highlightEnd)
+		pos = end
+	}
+
+	builder.WriteString(text[pos:])
+	return builder.String()
+}
+
+// separated reports whether a "--" line belongs between two lines of search
+// output because context was requested and lines between them were skipped.
+func separated(search *reader.SearchResult, prev, line reader.Line) bool {
+	if search == nil || (search.Before == 0 && search.After == 0) {
+		return false
+	}
+	if prev.Number > 0 && line.Number > 0 {
+		return line.Number != prev.Number+1
+	}
+	return false
+}
\ No newline at end of file
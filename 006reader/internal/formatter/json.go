@@ -17,16 +17,11 @@ func NewJSONFormatter(config *Config) *JSONFormatter {
 
 func (f *JSONFormatter) Format(content *reader.Content) (string, error) {
 	output := struct {
-		*reader.Content
+		document
 		FormatterInfo map[string]interface{} `json:"formatter_info"`
 	}{
-		Content: content,
-		FormatterInfo: map[string]interface{}{
-			"format":           "json",
-			"show_line_numbers": f.config.ShowLineNumbers,
-			"max_width":        f.config.MaxWidth,
-			"theme":            f.config.Theme,
-		},
+		document: newDocument(content),
+		FormatterInfo: f.info(),
 	}
 
 	jsonBytes, err := json.MarshalIndent(output, "", " ")
@@ -37,6 +32,53 @@ func (f *JSONFormatter) Format(content *reader.Content) (string, error) {
 	return string(jsonBytes), nil
 }
 
+// FormatAll renders several files as a single JSON array.
+func (f *JSONFormatter) FormatAll(contents []*reader.Content) (string, error) {
+	files := make([]document, len(contents))
+	for i, content := range contents {
+		files[i] = newDocument(content)
+	}
+
+	output := struct {
+		Files			[]document				`json:"files"`
+		FormatterInfo	map[string]interface{}	`json:"formatter_info"`
+	}{
+		Files: files,
+		FormatterInfo: f.info(),
+	}
+
+	jsonBytes, err := json.MarshalIndent(output, "", " ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal to JSON: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// document is a file as written out: its raw lines, or the records parsed
+// from them in their place.
+type document struct {
+	Lines		[]reader.Line		`json:"lines,omitempty"`
+	Records		*reader.Records		`json:"records,omitempty"`
+	Metadata	reader.Metadata		`json:"metadata"`
+}
+
+func newDocument(content *reader.Content) document {
+	if content.Records != nil {
+		return document{Records: content.Records, Metadata: content.Metadata}
+	}
+	return document{Lines: content.Lines, Metadata: content.Metadata}
+}
+
+func (f *JSONFormatter) info() map[string]interface{} {
+	return map[string]interface{}{
+		"format":           "json",
+		"show_line_numbers": f.config.ShowLineNumbers,
+		"max_width":        f.config.MaxWidth,
+		"theme":            f.config.Theme,
+	}
+}
+
 func (f *JSONFormatter) Name() string {
 	return "json"
 }
\ No newline at end of file
@@ -22,14 +22,27 @@ func (f *PlainFormatter) Format(content *reader.Content) (string, error) {
 		f.writeHeader(&builder, content)
 	}
 
-	for _, line := range content.Lines {
+	width := labelWidth(content.Lines)
+	for i, line := range content.Lines {
+		if i > 0 && separated(content.Metadata.Search, content.Lines[i-1], line) {
+			builder.WriteString("--\n")
+		}
+
 		if f.config.ShowLineNumbers{
-			lineNumStr := f.formatLineNumber(line.Number, len(content.Lines))
+			lineNumStr := f.formatLineNumber(line, width)
 			builder.WriteString(lineNumStr)
-			builder.WriteString(" | ")
+			// like grep, context lines are set apart from matches
+			if line.Context {
+				builder.WriteString(" - ")
+			} else {
+				builder.WriteString(" | ")
+			}
 		}
 
-		if f.config.MaxWidth > 0 && len(line.Content) > f.config.MaxWidth {
+		// highlighted matches are never wrapped so the spans stay intact
+		if f.config.ColorOutput && len(line.Matches) > 0 {
+			builder.WriteString(highlight(line.Content, line.Matches))
+		} else if f.config.MaxWidth > 0 && len(line.Content) > f.config.MaxWidth {
 			wrapped := f.wrapLine(line.Content, f.config.MaxWidth)
 			builder.WriteString(wrapped)
 		} else {
@@ -50,9 +63,8 @@ func (f *PlainFormatter) Name() string {
 	return "PlainFormatter"
 }
 
-func (f *PlainFormatter) formatLineNumber(lineNum, totalLines int) string {
-	width := len(fmt.Sprintf("%d", totalLines))
-	return fmt.Sprintf("%*d", width, lineNum)
+func (f *PlainFormatter) formatLineNumber(line reader.Line, width int) string {
+	return fmt.Sprintf("%*s", width, lineLabel(line))
 }
 
 func (f *PlainFormatter) wrapLine(content string, maxWidth int) string {
@@ -73,8 +85,8 @@ func (f *PlainFormatter) wrapLine(content string, maxWidth int) string {
 		} else {
 			if currentLine != "" {
 				result.WriteString(currentLine + "\n")
-				currentLine = word[maxWidth:]
 			}
+			currentLine = word
 		}
 	}
 
@@ -98,8 +110,30 @@ func (f *PlainFormatter) writeHeader(builder *strings.Builder, content *reader.C
 	builder.WriteString(separator + "\n")
 	builder.WriteString(fmt.Sprintf("File: %s\n", content.Metadata.FileName))
 	builder.WriteString(fmt.Sprintf("Path: %s\n", content.Metadata.FilePath))
-	builder.WriteString(fmt.Sprintf("Size: %s\n", content.Metadata.Size))
-	builder.WriteString(fmt.Sprintf("Lines: %s\n", content.Metadata.LineCount))
+	builder.WriteString(fmt.Sprintf("Size: %d\n", content.Metadata.Size))
+	if meta := content.Metadata; meta.Member != "" {
+		builder.WriteString(fmt.Sprintf("Member: %s (%s)\n", meta.Member, meta.Archive))
+	} else if meta.Archive != "" {
+		builder.WriteString(fmt.Sprintf("Archive: %s\n", meta.Archive))
+	}
+	if meta := content.Metadata; meta.Compression != "" || meta.Archive != "" {
+		if meta.Compression != "" {
+			builder.WriteString(fmt.Sprintf("Compression: %s\n", meta.Compression))
+		}
+		if meta.CompressedSize > 0 {
+			builder.WriteString(fmt.Sprintf("Compressed: %d bytes\n", meta.CompressedSize))
+		}
+		if meta.UncompressedSize > 0 {
+			builder.WriteString(fmt.Sprintf("Uncompressed: %d bytes\n", meta.UncompressedSize))
+		}
+	}
+	builder.WriteString(fmt.Sprintf("Lines: %d\n", content.Metadata.LineCount))
+	if content.Metadata.StartOffset > 0 || content.Metadata.EndOffset < content.Metadata.DataSize() {
+		builder.WriteString(fmt.Sprintf("Bytes: %d-%d\n", content.Metadata.StartOffset, content.Metadata.EndOffset))
+	}
+	if search := content.Metadata.Search; search != nil {
+		builder.WriteString(fmt.Sprintf("Search: %s\n", strings.Join(search.Patterns, ", ")))
+	}
 	builder.WriteString(fmt.Sprintf("Modified: %s\n", content.Metadata.ModTime.Format("2006-01-02 15:04:05")))
 	builder.WriteString(separator + "\n\n")
 }
@@ -108,6 +142,9 @@ func (f *PlainFormatter) writeFooter(builder *strings.Builder, content *reader.C
 	separator := strings.Repeat("-", 50)
 	builder.WriteString("\n" + separator + "\n")
 	builder.WriteString(fmt.Sprintf("Read %d lines in %v\n", content.Metadata.LineCount, content.Metadata.ReadTime))
+	if search := content.Metadata.Search; search != nil {
+		builder.WriteString(fmt.Sprintf("Matched %d lines\n", search.MatchCount))
+	}
 	builder.WriteString(fmt.Sprintf("Encoding: %s\n", content.Metadata.Encoding))
 	builder.WriteString(separator + "\n")
 }
\ No newline at end of file
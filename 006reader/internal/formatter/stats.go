@@ -0,0 +1,150 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samnart1/GoLang/006reader/internal/reader"
+)
+
+// statRow is one labelled figure of a Stats
+type statRow struct {
+	label	string
+	value	func(s *reader.Stats) string
+}
+
+var statRows = []statRow{
+	{"Lines", func(s *reader.Stats) string { return strconv.Itoa(s.Lines) }},
+	{"Words", func(s *reader.Stats) string { return strconv.Itoa(s.Words) }},
+	{"Characters", func(s *reader.Stats) string { return strconv.Itoa(s.Chars) }},
+	{"Bytes", func(s *reader.Stats) string { return strconv.FormatInt(s.Metadata.DataSize(), 10) }},
+	{"Empty lines", func(s *reader.Stats) string { return strconv.Itoa(s.EmptyLines) }},
+	{"Longest line", longestLine},
+	{"Line endings", func(s *reader.Stats) string { return s.LineEnding }},
+	{"Final newline", func(s *reader.Stats) string { return yesNo(s.FinalNewline) }},
+	{"Trailing whitespace", func(s *reader.Stats) string { return fmt.Sprintf("%d lines", s.TrailingWhitespace) }},
+	{"Encoding", encodingLabel},
+}
+
+func longestLine(s *reader.Stats) string {
+	if s.LongestLineNumber > 0 {
+		return fmt.Sprintf("%d (line %d)", s.LongestLine, s.LongestLineNumber)
+	}
+	return strconv.Itoa(s.LongestLine)
+}
+
+func encodingLabel(s *reader.Stats) string {
+	label := s.Metadata.Encoding
+	if s.Metadata.BOM {
+		label += " with BOM"
+	}
+	if s.Metadata.Compression != "" {
+		label += ", " + s.Metadata.Compression
+	}
+	return label
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func (f *PlainFormatter) FormatStats(stats []*reader.Stats) (string, error) {
+	var builder strings.Builder
+
+	for i, s := range stats {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(fmt.Sprintf("File: %s\n", s.Metadata.FilePath))
+		for _, row := range statRows {
+			builder.WriteString(fmt.Sprintf("%-20s %s\n", row.label+":", row.value(s)))
+		}
+
+		if len(s.TopLines) > 0 {
+			builder.WriteString("Top repeated lines:\n")
+			for _, line := range s.TopLines {
+				builder.WriteString(fmt.Sprintf("%8d  %s\n", line.Count, line.Content))
+			}
+		}
+	}
+
+	return strings.TrimSuffix(builder.String(), "\n"), nil
+}
+
+func (f *JSONFormatter) FormatStats(stats []*reader.Stats) (string, error) {
+	var output any = stats[0]
+	if len(stats) > 1 {
+		output = struct {
+			Files	[]*reader.Stats	`json:"files"`
+		}{Files: stats}
+	}
+
+	jsonBytes, err := json.MarshalIndent(output, "", " ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal to JSON: %w", err)
+	}
+	return string(jsonBytes), nil
+}
+
+// FormatStats puts the files side by side, one column each, followed by the
+// top repeated lines of every file that has any.
+func (f *TableFormatter) FormatStats(stats []*reader.Stats) (string, error) {
+	headers := []string{"Statistic"}
+	for _, s := range stats {
+		headers = append(headers, s.Metadata.FileName)
+	}
+
+	rows := make([][]string, len(statRows))
+	for i, row := range statRows {
+		rows[i] = []string{row.label}
+		for _, s := range stats {
+			rows[i] = append(rows[i], row.value(s))
+		}
+	}
+
+	var builder strings.Builder
+	f.writeTable(&builder, headers, rows, nil)
+
+	for _, s := range stats {
+		if len(s.TopLines) == 0 {
+			continue
+		}
+
+		builder.WriteString(fmt.Sprintf("\nTop repeated lines in %s\n", s.Metadata.FilePath))
+		rows := make([][]string, len(s.TopLines))
+		for i, line := range s.TopLines {
+			rows[i] = []string{strconv.Itoa(line.Count), line.Content}
+		}
+		f.writeTable(&builder, []string{"Count", "Line"}, rows, []bool{true, false})
+	}
+
+	return strings.TrimSuffix(builder.String(), "\n"), nil
+}
+
+// writeTable draws headers and rows with columns as wide as their widest
+// cell, narrowed to fit the maximum width.
+func (f *TableFormatter) writeTable(builder *strings.Builder, headers []string, rows [][]string, rightAlign []bool) {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = len([]rune(header))
+		for _, row := range rows {
+			if n := len([]rune(row[i])); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	f.fitColumns(widths)
+
+	writeBorder(builder, widths, "┌", "┬", "┐")
+	writeCells(builder, headers, widths, nil)
+	writeBorder(builder, widths, "├", "┼", "┤")
+	for _, row := range rows {
+		writeCells(builder, row, widths, rightAlign)
+	}
+	writeBorder(builder, widths, "└", "┴", "┘")
+}
\ No newline at end of file
@@ -0,0 +1,142 @@
+package formatter
+
+import (
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/samnart1/GoLang/006reader/internal/reader"
+)
+
+// languages maps the file types of reader.GetFileType to chroma lexers
+var languages = map[string]string{
+	"go":		"go",
+	"json":		"json",
+	"yaml":		"yaml",
+	"markdown":	"markdown",
+	"shell":	"bash",
+	"sql":		"sql",
+	"xml":		"xml",
+}
+
+// syntaxThemes gives the formatter's own themes a color scheme; any other
+// theme is taken as the name of a chroma style such as dracula or github
+var syntaxThemes = map[string]string{
+	"default":	"monokai",
+	"detailed":	"monokai",
+	"minimal":	"bw",
+}
+
+// SyntaxFormatter colors source code by language, which is picked from the
+// file extension and otherwise guessed from the content. Header and footer
+// are the plain formatter's.
+type SyntaxFormatter struct {
+	config	*Config
+	plain	*PlainFormatter
+}
+
+func NewSyntaxFormatter(config *Config) *SyntaxFormatter {
+	return &SyntaxFormatter{config: config, plain: NewPlainFormatter(config)}
+}
+
+func (f *SyntaxFormatter) Format(content *reader.Content) (string, error) {
+	var builder strings.Builder
+
+	if f.plain.shouldShowHeader() {
+		f.plain.writeHeader(&builder, content)
+	}
+
+	lines := f.highlight(content)
+
+	width := labelWidth(content.Lines)
+	for i, line := range content.Lines {
+		if f.config.ShowLineNumbers {
+			builder.WriteString(f.plain.formatLineNumber(line, width))
+			builder.WriteString(" | ")
+		}
+		builder.WriteString(lines[i])
+		builder.WriteString("\n")
+	}
+
+	if f.plain.shouldShowFooter() {
+		f.plain.writeFooter(&builder, content)
+	}
+
+	return builder.String(), nil
+}
+
+func (f *SyntaxFormatter) Name() string {
+	return "SyntaxFormatter"
+}
+
+// highlight returns the lines of content with color codes added. The lines
+// are lexed together so tokens spanning lines, like block comments, are
+// colored throughout. Without color, or without a lexer, they come back as
+// they are.
+func (f *SyntaxFormatter) highlight(content *reader.Content) []string {
+	plain := make([]string, len(content.Lines))
+	for i, line := range content.Lines {
+		plain[i] = line.Content
+	}
+	if !f.config.ColorOutput || len(plain) == 0 {
+		return plain
+	}
+
+	text := strings.Join(plain, "\n") + "\n"
+	lexer := lexerFor(content.Metadata.FilePath, text)
+	if lexer == nil {
+		return plain
+	}
+
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, text)
+	if err != nil {
+		return plain
+	}
+
+	style := syntaxStyle(f.config.Theme)
+	terminal := formatters.Get("terminal256")
+
+	tokenLines := chroma.SplitTokensIntoLines(iterator.Tokens())
+	colored := make([]string, len(plain))
+	for i := range colored {
+		if i >= len(tokenLines) {
+			colored[i] = plain[i]
+			continue
+		}
+
+		var line strings.Builder
+		if err := terminal.Format(&line, style, chroma.Literator(tokenLines[i]...)); err != nil {
+			colored[i] = plain[i]
+			continue
+		}
+		colored[i] = strings.TrimRight(line.String(), "\n")
+	}
+
+	return colored
+}
+
+// lexerFor picks the lexer for a file from its type, falling back to guessing
+// from text. It returns nil when the language is unknown.
+func lexerFor(filePath, text string) chroma.Lexer {
+	if name, ok := languages[reader.GetFileType(filePath)]; ok {
+		return lexers.Get(name)
+	}
+	if lexer := lexers.Analyse(text); lexer != nil {
+		return lexer
+	}
+	return nil
+}
+
+// syntaxStyle resolves a theme to a chroma style; unknown names get the
+// default scheme.
+func syntaxStyle(theme string) *chroma.Style {
+	if name, ok := syntaxThemes[theme]; ok {
+		theme = name
+	}
+	if style, ok := styles.Registry[strings.ToLower(theme)]; ok {
+		return style
+	}
+	return styles.Get(syntaxThemes["default"])
+}
\ No newline at end of file
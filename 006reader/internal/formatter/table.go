@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/samnart1/GoLang/006reader/internal/reader"
 )
@@ -17,13 +18,20 @@ func NewTableFormatter(config *Config) *TableFormatter {
 }
 
 func (f *TableFormatter) Format(content *reader.Content) (string, error) {
+	if content.Records != nil {
+		return f.formatRecords(content), nil
+	}
+
 	if len(content.Lines) == 0 {
 		return "No content to display\n", nil
 	}
 
 	var builder strings.Builder
 
-	lineNumWidth := len(strconv.Itoa(len(content.Lines)))
+	lineNumWidth := labelWidth(content.Lines)
+	if lineNumWidth < len("Line") {
+		lineNumWidth = len("Line")
+	}
 	contentWidth := f.calculateContentWidth(content.Lines)
 
 	if f.config.MaxWidth > 0 {
@@ -66,10 +74,10 @@ func (f *TableFormatter) calculateContentWidth(lines []reader.Line) int {
 
 func (f *TableFormatter) writeTableHeader(builder *strings.Builder, lineWidth, contentWidth int) {
 	//top border
-	builder.WriteString("r")
-	builder.WriteString(strings.Repeat("-", lineWidth+2))
-	builder.WriteString("T")
-	builder.WriteString(strings.Repeat("-", contentWidth+2))
+	builder.WriteString("┌")
+	builder.WriteString(strings.Repeat("─", lineWidth+2))
+	builder.WriteString("┬")
+	builder.WriteString(strings.Repeat("─", contentWidth+2))
 	builder.WriteString("┐\n")
 
 	//header row
@@ -91,13 +99,24 @@ func (f *TableFormatter) writeTableRow(builder *strings.Builder, line reader.Lin
 	content := line.Content
 
 	if len(content) > contentWidth {
-		content = content[:contentWidth-3] + "..."
+		content = content[:contentWidth-3]
 	}
 
+	// pad before highlighting so the color codes do not count as width
+	cell := content
+	if f.config.ColorOutput && len(line.Matches) > 0 {
+		cell = highlight(content, line.Matches)
+	}
+	if len(content) < len(line.Content) {
+		cell += "..."
+		content += "..."
+	}
+	cell += strings.Repeat(" ", contentWidth-len(content))
+
 	builder.WriteString("|")
-	builder.WriteString(fmt.Sprintf(" %*d ", lineWidth, line.Number))
+	builder.WriteString(fmt.Sprintf(" %*s ", lineWidth, lineLabel(line)))
 	builder.WriteString("|")
-	builder.WriteString(fmt.Sprintf(" %-*s ", contentWidth, content))
+	builder.WriteString(fmt.Sprintf(" %s ", cell))
 	builder.WriteString("|\n")
 }
 
@@ -109,8 +128,136 @@ func (f *TableFormatter) writeTableFooter(builder *strings.Builder, lineWidth, c
 	builder.WriteString(strings.Repeat("─", contentWidth+2))
 	builder.WriteString("┘\n")
 
-	builder.WriteString(fmt.Sprintf("\nSummary: %d lines, %d bytes, read in %v\n", 
+	size := fmt.Sprintf("%d bytes", content.Metadata.Size)
+	if content.Metadata.Compression != "" || content.Metadata.Archive != "" {
+		size = fmt.Sprintf("%d bytes uncompressed", content.Metadata.DataSize())
+	}
+
+	builder.WriteString(fmt.Sprintf("\nSummary: %d lines, %s, %s, read in %v\n", 
 		content.Metadata.LineCount,
-		content.Metadata.Size,
+		size,
+		content.Metadata.Encoding,
 		content.Metadata.ReadTime))
+
+	if search := content.Metadata.Search; search != nil {
+		builder.WriteString(fmt.Sprintf("Matched %d lines for %s\n", search.MatchCount, strings.Join(search.Patterns, ", ")))
+	}
+}
+
+// formatRecords renders parsed records with one column per field. Numeric
+// columns are right aligned and wide columns are cut down to fit MaxWidth.
+func (f *TableFormatter) formatRecords(content *reader.Content) string {
+	records := content.Records
+	if len(records.Rows) == 0 {
+		return "No records to display\n"
+	}
+
+	headers := append([]string{"Line"}, records.Columns...)
+	rows := make([][]string, len(records.Rows))
+	for i, record := range records.Rows {
+		row := []string{strconv.Itoa(record.Line)}
+		if record.Line == 0 {
+			row[0] = "@" + strconv.FormatInt(record.Offset, 10)
+		}
+		for _, column := range records.Columns {
+			row = append(row, record.Text(column))
+		}
+		rows[i] = row
+	}
+
+	widths := make([]int, len(headers))
+	numeric := make([]bool, len(headers))
+	for i, header := range headers {
+		widths[i] = utf8.RuneCountInString(header)
+		numeric[i] = true
+		for _, row := range rows {
+			if n := utf8.RuneCountInString(row[i]); n > widths[i] {
+				widths[i] = n
+			}
+			if _, err := strconv.ParseFloat(strings.TrimPrefix(row[i], "@"), 64); row[i] != "" && err != nil {
+				numeric[i] = false
+			}
+		}
+		if widths[i] > maxColumnWidth {
+			widths[i] = maxColumnWidth
+		}
+	}
+	f.fitColumns(widths)
+
+	var builder strings.Builder
+	writeBorder(&builder, widths, "┌", "┬", "┐")
+	writeCells(&builder, headers, widths, nil)
+	writeBorder(&builder, widths, "├", "┼", "┤")
+	for _, row := range rows {
+		writeCells(&builder, row, widths, numeric)
+	}
+	writeBorder(&builder, widths, "└", "┴", "┘")
+
+	builder.WriteString(fmt.Sprintf("\nSummary: %d %s records, %d columns, %s, read in %v\n",
+		len(records.Rows),
+		records.Format,
+		len(records.Columns),
+		content.Metadata.Encoding,
+		content.Metadata.ReadTime))
+	if records.Unparsed > 0 {
+		builder.WriteString(fmt.Sprintf("Skipped %d lines that did not parse as %s\n", records.Unparsed, records.Format))
+	}
+
+	return builder.String()
+}
+
+// maxColumnWidth keeps one long field from pushing the others off screen.
+const maxColumnWidth = 40
+
+// fitColumns narrows the widest columns until the table fits MaxWidth or
+// every column is down to a readable minimum.
+func (f *TableFormatter) fitColumns(widths []int) {
+	if f.config.MaxWidth <= 0 {
+		return
+	}
+
+	for {
+		total := 1
+		widest := 0
+		for i, width := range widths {
+			total += width + 3
+			if width > widths[widest] {
+				widest = i
+			}
+		}
+		if total <= f.config.MaxWidth || widths[widest] <= 8 {
+			return
+		}
+		widths[widest]--
+	}
+}
+
+func writeBorder(builder *strings.Builder, widths []int, left, mid, right string) {
+	builder.WriteString(left)
+	for i, width := range widths {
+		if i > 0 {
+			builder.WriteString(mid)
+		}
+		builder.WriteString(strings.Repeat("─", width+2))
+	}
+	builder.WriteString(right + "\n")
+}
+
+func writeCells(builder *strings.Builder, cells []string, widths []int, rightAlign []bool) {
+	builder.WriteString("|")
+	for i, cell := range cells {
+		runes := []rune(cell)
+		if len(runes) > widths[i] {
+			cell = string(runes[:widths[i]-3]) + "..."
+			runes = []rune(cell)
+		}
+
+		padding := strings.Repeat(" ", widths[i]-len(runes))
+		if rightAlign != nil && rightAlign[i] {
+			builder.WriteString(" " + padding + cell + " |")
+		} else {
+			builder.WriteString(" " + cell + padding + " |")
+		}
+	}
+	builder.WriteString("\n")
 }
\ No newline at end of file
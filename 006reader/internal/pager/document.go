@@ -0,0 +1,175 @@
+package pager
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/samnart1/GoLang/006reader/internal/reader"
+)
+
+// document gives random access to the lines of a file by remembering where
+// each line starts. The index grows only as far as the pager has looked, and
+// line text is read back from disk on demand.
+type document struct {
+	path		string
+	file		*os.File
+	bufferSize	int
+	charset		*reader.Charset
+	offsets		[]int64
+	// next is the offset just past the last indexed line
+	next		int64
+	complete	bool
+}
+
+func openDocument(path string, bufferSize int, encoding string) (*document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	charset, err := reader.ResolveCharset(file, encoding)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &document{
+		path: path,
+		file: file,
+		bufferSize: bufferSize,
+		charset: charset,
+		next: int64(charset.BOMLength),
+	}, nil
+}
+
+func (d *document) Close() error {
+	return d.file.Close()
+}
+
+// known is the number of lines indexed so far.
+func (d *document) known() int {
+	return len(d.offsets)
+}
+
+// ensure indexes lines until at least n are known or the file ends.
+func (d *document) ensure(n int) error {
+	if d.complete || len(d.offsets) >= n {
+		return nil
+	}
+
+	scanner, err := d.scanFrom(d.next)
+	if err != nil {
+		return err
+	}
+
+	for len(d.offsets) < n {
+		_, offset, err := scanner.Next()
+		if err == io.EOF {
+			d.complete = true
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to index %s: %w", d.path, err)
+		}
+		d.offsets = append(d.offsets, offset)
+		d.next = scanner.Offset()
+	}
+
+	return nil
+}
+
+// total indexes the whole file and returns its line count.
+func (d *document) total() (int, error) {
+	for !d.complete {
+		if err := d.ensure(len(d.offsets) + 4096); err != nil {
+			return 0, err
+		}
+	}
+	return len(d.offsets), nil
+}
+
+// lines returns up to count lines starting at index from.
+func (d *document) lines(from, count int) ([]string, error) {
+	if err := d.ensure(from + count); err != nil {
+		return nil, err
+	}
+	if from >= len(d.offsets) {
+		return nil, nil
+	}
+
+	scanner, err := d.scanFrom(d.offsets[from])
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for i := from; i < from+count && i < len(d.offsets); i++ {
+		text, _, err := scanner.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", d.path, err)
+		}
+		lines = append(lines, text)
+	}
+
+	return lines, nil
+}
+
+// find returns the index of the first line after from (or before it when
+// searching backwards) that contains pattern.
+func (d *document) find(from int, pattern string, forward bool) (int, bool, error) {
+	if !forward {
+		for i := from - 1; i >= 0; i-- {
+			lines, err := d.lines(i, 1)
+			if err != nil {
+				return 0, false, err
+			}
+			if len(lines) > 0 && strings.Contains(lines[0], pattern) {
+				return i, true, nil
+			}
+		}
+		return 0, false, nil
+	}
+
+	start := from + 1
+	if err := d.ensure(start + 1); err != nil {
+		return 0, false, err
+	}
+	if start >= len(d.offsets) {
+		return 0, false, nil
+	}
+
+	// scan sequentially, extending the index on the way
+	scanner, err := d.scanFrom(d.offsets[start])
+	if err != nil {
+		return 0, false, err
+	}
+	for i := start; ; i++ {
+		text, offset, err := scanner.Next()
+		if err == io.EOF {
+			d.complete = true
+			return 0, false, nil
+		}
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to search %s: %w", d.path, err)
+		}
+		if i == len(d.offsets) {
+			d.offsets = append(d.offsets, offset)
+			d.next = scanner.Offset()
+		}
+		if strings.Contains(text, pattern) {
+			return i, true, nil
+		}
+	}
+}
+
+func (d *document) scanFrom(offset int64) (*reader.LineScanner, error) {
+	if _, err := d.file.Seek(offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek %s: %w", d.path, err)
+	}
+	return reader.NewLineScanner(d.file, offset, d.bufferSize, d.charset), nil
+}
\ No newline at end of file
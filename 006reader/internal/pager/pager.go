@@ -0,0 +1,429 @@
+package pager
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+type Options struct {
+	ShowLineNumbers	bool
+	// StartLine is the 1-based line the first file opens at
+	StartLine		int
+	// StartAtEnd opens the first file at its last page
+	StartAtEnd		bool
+	BufferSize		int
+	// Encoding names the files' charset; "auto" or "" detects it
+	Encoding		string
+}
+
+// Pager shows files one screen at a time in the terminal. Only the visible
+// lines are held in memory, so files of any size can be paged.
+type Pager struct {
+	in		*os.File
+	out		*os.File
+	files	[]string
+	opts	Options
+
+	doc		*document
+	index	int
+	top		int
+	left	int
+	rows	int
+	cols	int
+	pattern	string
+	message	string
+	pending	[]byte
+}
+
+func New(files []string, opts Options) *Pager {
+	return &Pager{
+		in: os.Stdin,
+		out: os.Stdout,
+		files: files,
+		opts: opts,
+	}
+}
+
+// IsTerminal reports whether f is attached to a terminal, which paging needs.
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+func (p *Pager) Run() error {
+	if len(p.files) == 0 {
+		return fmt.Errorf("no files to page")
+	}
+
+	state, err := term.MakeRaw(int(p.in.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	defer term.Restore(int(p.in.Fd()), state)
+
+	// alternate screen with the cursor hidden, restored on the way out
+	fmt.Fprint(p.out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(p.out, "\x1b[?25h\x1b[?1049l")
+
+	if err := p.open(0); err != nil {
+		return err
+	}
+	defer func() { p.doc.Close() }()
+
+	if err := p.resize(); err != nil {
+		return err
+	}
+	if p.opts.StartAtEnd {
+		if err := p.bottom(); err != nil {
+			return err
+		}
+	} else if p.opts.StartLine > 1 {
+		if err := p.scroll(p.opts.StartLine - 1); err != nil {
+			return err
+		}
+	}
+
+	for {
+		if err := p.render(); err != nil {
+			return err
+		}
+
+		key, err := p.readKey()
+		if err != nil {
+			return err
+		}
+
+		p.message = ""
+		quit, err := p.handle(key)
+		if err != nil {
+			return err
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+func (p *Pager) handle(key string) (bool, error) {
+	page := p.rows
+	if page < 1 {
+		page = 1
+	}
+
+	switch key {
+	case "q", "Q", "ctrl-c":
+		return true, nil
+	case "j", "down", "enter":
+		return false, p.scroll(1)
+	case "k", "up":
+		return false, p.scroll(-1)
+	case " ", "f", "pgdn":
+		return false, p.scroll(page)
+	case "b", "pgup":
+		return false, p.scroll(-page)
+	case "d":
+		return false, p.scroll(page / 2)
+	case "u":
+		return false, p.scroll(-page / 2)
+	case "g", "home":
+		p.top = 0
+	case "G", "end":
+		return false, p.bottom()
+	case "right":
+		p.left += 8
+	case "left":
+		if p.left -= 8; p.left < 0 {
+			p.left = 0
+		}
+	case "/":
+		pattern, ok, err := p.prompt("/")
+		if err != nil || !ok {
+			return false, err
+		}
+		if pattern != "" {
+			p.pattern = pattern
+		}
+		return false, p.search(true)
+	case "n":
+		return false, p.search(true)
+	case "N":
+		return false, p.search(false)
+	case "]":
+		if p.index+1 < len(p.files) {
+			return false, p.open(p.index + 1)
+		}
+		p.message = "Last file"
+	case "[":
+		if p.index > 0 {
+			return false, p.open(p.index - 1)
+		}
+		p.message = "First file"
+	}
+
+	return false, nil
+}
+
+func (p *Pager) open(index int) error {
+	doc, err := openDocument(p.files[index], p.opts.BufferSize, p.opts.Encoding)
+	if err != nil {
+		return err
+	}
+	if p.doc != nil {
+		p.doc.Close()
+	}
+
+	p.doc = doc
+	p.index = index
+	p.top = 0
+	p.left = 0
+	return nil
+}
+
+// scroll moves the view by delta lines, stopping at either end of the file.
+func (p *Pager) scroll(delta int) error {
+	top := p.top + delta
+	if top < 0 {
+		top = 0
+	}
+
+	if err := p.doc.ensure(top + p.rows); err != nil {
+		return err
+	}
+	if last := p.doc.known() - p.rows; p.doc.complete && top > last {
+		top = last
+	}
+	if top < 0 {
+		top = 0
+	}
+
+	p.top = top
+	return nil
+}
+
+func (p *Pager) bottom() error {
+	total, err := p.doc.total()
+	if err != nil {
+		return err
+	}
+	if p.top = total - p.rows; p.top < 0 {
+		p.top = 0
+	}
+	return nil
+}
+
+func (p *Pager) search(forward bool) error {
+	if p.pattern == "" {
+		p.message = "No previous pattern"
+		return nil
+	}
+
+	line, found, err := p.doc.find(p.top, p.pattern, forward)
+	if err != nil {
+		return err
+	}
+	if !found {
+		p.message = "Pattern not found: " + p.pattern
+		return nil
+	}
+
+	p.top = line
+	return nil
+}
+
+// resize picks up the terminal size, which is checked before every render
+// so resizing the window needs no signal handling.
+func (p *Pager) resize() error {
+	cols, rows, err := term.GetSize(int(p.out.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to get terminal size: %w", err)
+	}
+	// the last row is the status line
+	p.cols, p.rows = cols, rows-1
+	if p.rows < 1 {
+		p.rows = 1
+	}
+	return nil
+}
+
+func (p *Pager) render() error {
+	if err := p.resize(); err != nil {
+		return err
+	}
+
+	lines, err := p.doc.lines(p.top, p.rows)
+	if err != nil {
+		return err
+	}
+
+	gutter := 0
+	if p.opts.ShowLineNumbers {
+		gutter = len(strconv.Itoa(p.top+p.rows)) + 1
+	}
+
+	var screen strings.Builder
+	screen.WriteString("\x1b[H\x1b[2J")
+
+	for i := 0; i < p.rows; i++ {
+		if i >= len(lines) {
+			screen.WriteString("~\r\n")
+			continue
+		}
+		if gutter > 0 {
+			screen.WriteString(fmt.Sprintf("\x1b[2m%*d\x1b[0m ", gutter-1, p.top+i+1))
+		}
+		screen.WriteString(visible(lines[i], p.left, p.cols-gutter))
+		screen.WriteString("\r\n")
+	}
+
+	screen.WriteString("\x1b[7m")
+	screen.WriteString(visible(p.status(len(lines)), 0, p.cols))
+	screen.WriteString("\x1b[0m")
+
+	_, err = fmt.Fprint(p.out, screen.String())
+	return err
+}
+
+func (p *Pager) status(shown int) string {
+	total := "?"
+	if p.doc.complete {
+		total = strconv.Itoa(p.doc.known())
+	}
+
+	status := fmt.Sprintf(" %s  lines %d-%d of %s  %s", p.doc.path, p.top+1, p.top+shown, total, p.doc.charset.Name)
+	if len(p.files) > 1 {
+		status += fmt.Sprintf("  (file %d of %d)", p.index+1, len(p.files))
+	}
+	if p.message != "" {
+		return status + "  " + p.message
+	}
+	return status + "  [q]uit [/]search [space/b]page"
+}
+
+// prompt reads a line of input on the status line. It returns false if the
+// user pressed escape.
+func (p *Pager) prompt(label string) (string, bool, error) {
+	var input []rune
+	for {
+		fmt.Fprintf(p.out, "\x1b[%d;1H\x1b[2K%s%s", p.rows+1, label, string(input))
+
+		key, err := p.readKey()
+		if err != nil {
+			return "", false, err
+		}
+
+		switch key {
+		case "enter":
+			return string(input), true, nil
+		case "esc", "ctrl-c":
+			return "", false, nil
+		case "backspace":
+			if len(input) > 0 {
+				input = input[:len(input)-1]
+			}
+		default:
+			if r := []rune(key); len(r) == 1 && unicode.IsPrint(r[0]) {
+				input = append(input, r[0])
+			}
+		}
+	}
+}
+
+// readKey returns the next key press. Input arriving in one read, such as
+// pasted text, is buffered and handed out a key at a time.
+func (p *Pager) readKey() (string, error) {
+	if len(p.pending) == 0 {
+		buf := make([]byte, 64)
+		n, err := p.in.Read(buf)
+		if err != nil {
+			return "", fmt.Errorf("failed to read key: %w", err)
+		}
+		p.pending = buf[:n]
+	}
+
+	key, size := parseKey(p.pending)
+	p.pending = p.pending[size:]
+	return key, nil
+}
+
+// parseKey decodes the key at the start of b and how many bytes it used.
+func parseKey(b []byte) (string, int) {
+	switch {
+	case len(b) == 0:
+		return "", 0
+	case len(b) >= 3 && b[0] == 0x1b && b[1] == '[':
+		// CSI sequences end with a letter or a tilde
+		end := 2
+		for end < len(b) && (b[end] < 0x40 || b[end] > 0x7e) {
+			end++
+		}
+		if end == len(b) {
+			return "", len(b)
+		}
+		switch string(b[2:end+1]) {
+		case "A":
+			return "up", end + 1
+		case "B":
+			return "down", end + 1
+		case "C":
+			return "right", end + 1
+		case "D":
+			return "left", end + 1
+		case "H", "1~":
+			return "home", end + 1
+		case "F", "4~":
+			return "end", end + 1
+		case "5~":
+			return "pgup", end + 1
+		case "6~":
+			return "pgdn", end + 1
+		}
+		return "", end + 1
+	case b[0] == 0x1b:
+		return "esc", 1
+	case b[0] == '\r' || b[0] == '\n':
+		return "enter", 1
+	case b[0] == 0x7f || b[0] == 0x08:
+		return "backspace", 1
+	case b[0] == 0x03:
+		return "ctrl-c", 1
+	}
+
+	r, size := utf8.DecodeRune(b)
+	return string(r), size
+}
+
+// visible expands tabs, masks control characters and cuts the line to the
+// columns that fit on screen after scrolling left columns to the right.
+func visible(line string, left, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	var out []rune
+	col := 0
+	for _, r := range line {
+		text := []rune{r}
+		if r == '\t' {
+			text = []rune(strings.Repeat(" ", 4-col%4))
+		} else if !unicode.IsPrint(r) {
+			text = []rune{'.'}
+		}
+
+		for _, c := range text {
+			if col >= left {
+				out = append(out, c)
+			}
+			col++
+		}
+		if len(out) >= width {
+			return string(out[:width])
+		}
+	}
+
+	return string(out)
+}
\ No newline at end of file
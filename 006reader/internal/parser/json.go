@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// decodeJSON decodes numbers as json.Number so large integers keep their
+// exact digits.
+func decodeJSON(line string, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(line)))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// objectKeys returns the top-level keys of a JSON object in the order they
+// are written, which a map loses.
+func objectKeys(line string) ([]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(line)))
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("not a JSON object")
+	}
+
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid object key")
+		}
+		keys = append(keys, key)
+
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,394 @@
+package parser
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/samnart1/GoLang/006reader/internal/reader"
+)
+
+const (
+	FormatCSV		= "csv"
+	FormatTSV		= "tsv"
+	FormatJSONL		= "jsonl"
+	FormatLogfmt	= "logfmt"
+	FormatApache	= "apache"
+)
+
+// sampleLines is how many lines detection looks at.
+const sampleLines = 20
+
+// apacheLine matches the Apache/Nginx common and combined log formats.
+var apacheLine = regexp.MustCompile(`^(\S+) (\S+) (\S+) \[([^\]]+)\] "([^"]*)" (\d{3}) (\S+)(?: "([^"]*)" "([^"]*)")?`)
+
+var apacheColumns = []string{"remote_addr", "ident", "user", "time", "request", "status", "bytes", "referer", "user_agent"}
+
+func GetAvailableFormats() []string {
+	return []string{FormatCSV, FormatTSV, FormatJSONL, FormatLogfmt, FormatApache}
+}
+
+// Detect guesses the structured format of the content from the file
+// extension and a sample of its lines. It returns "" for plain text.
+func Detect(content *reader.Content) string {
+	// "data.csv.gz" is a CSV file once decompressed
+	name := content.Metadata.FileName
+	if content.Metadata.Compression != "" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV
+	case ".tsv", ".tab":
+		return FormatTSV
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	}
+
+	var sample []string
+	for _, line := range content.Lines {
+		if strings.TrimSpace(line.Content) == "" {
+			continue
+		}
+		if sample = append(sample, line.Content); len(sample) == sampleLines {
+			break
+		}
+	}
+	if len(sample) == 0 {
+		return ""
+	}
+
+	switch {
+	case all(sample, isJSONObject):
+		return FormatJSONL
+	case all(sample, apacheLine.MatchString):
+		return FormatApache
+	case all(sample, isLogfmt):
+		return FormatLogfmt
+	case consistentFields(sample, '\t'):
+		return FormatTSV
+	case consistentFields(sample, ','):
+		return FormatCSV
+	}
+	return ""
+}
+
+// Parse splits the content's lines into records. CSV and TSV take their
+// column names from header, or from the first line when header is nil and
+// the content starts at the top of the file.
+func Parse(content *reader.Content, format string, header []string) (*reader.Records, error) {
+	switch format {
+	case FormatCSV:
+		return parseDelimited(content, format, ',', header), nil
+	case FormatTSV:
+		return parseDelimited(content, format, '\t', header), nil
+	case FormatJSONL:
+		return parseEach(content, format, nil, parseJSON), nil
+	case FormatLogfmt:
+		return parseEach(content, format, nil, parseLogfmt), nil
+	case FormatApache:
+		return parseEach(content, format, apacheColumns, parseApache), nil
+	default:
+		return nil, fmt.Errorf("unsupported structured format: %s", format)
+	}
+}
+
+// ParseHeader splits a header line of a CSV or TSV file into column names.
+func ParseHeader(line, format string) ([]string, error) {
+	comma := ','
+	if format == FormatTSV {
+		comma = '\t'
+	}
+
+	fields, ok := splitDelimited(line, comma)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s header", format)
+	}
+	return fields, nil
+}
+
+// parseDelimited reads each run of consecutive lines as one stream, so quoted
+// fields may span lines. A record is numbered by the line it starts on.
+func parseDelimited(content *reader.Content, format string, comma rune, header []string) *reader.Records {
+	records := &reader.Records{Format: format, Columns: header}
+	readHeader := header == nil && len(content.Lines) > 0 && content.Lines[0].Number == 1
+
+	for _, run := range consecutiveRuns(content.Lines) {
+		texts := make([]string, len(run))
+		for i, line := range run {
+			texts[i] = line.Content
+		}
+
+		r := csv.NewReader(strings.NewReader(strings.Join(texts, "\n")))
+		r.Comma = comma
+		r.FieldsPerRecord = -1
+
+		for {
+			before := r.InputOffset()
+			fields, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				// a quote that is never closed swallows the rest of the run
+				records.Unparsed++
+				if r.InputOffset() == before {
+					break
+				}
+				continue
+			}
+
+			if readHeader {
+				records.Columns = fields
+				readHeader = false
+				continue
+			}
+
+			// name columns the header does not cover by position
+			for len(records.Columns) < len(fields) {
+				records.Columns = append(records.Columns, fmt.Sprintf("column%d", len(records.Columns)+1))
+			}
+
+			startLine, _ := r.FieldPos(0)
+			record := newRecord(run[startLine-1])
+			for i, field := range fields {
+				record.Fields[records.Columns[i]] = field
+			}
+			records.Rows = append(records.Rows, record)
+		}
+	}
+
+	return records
+}
+
+// consecutiveRuns splits lines wherever line numbers skip, as they do
+// between search matches.
+func consecutiveRuns(lines []reader.Line) [][]reader.Line {
+	var runs [][]reader.Line
+	start := 0
+	for i := 1; i <= len(lines); i++ {
+		if i == len(lines) || lines[i].Number != lines[i-1].Number+1 {
+			runs = append(runs, lines[start:i])
+			start = i
+		}
+	}
+	return runs
+}
+
+// parseEach parses every line on its own, collecting columns in the order
+// they first appear unless fixed columns are given.
+func parseEach(content *reader.Content, format string, columns []string, parse func(string) ([]string, map[string]any, bool)) *reader.Records {
+	records := &reader.Records{Format: format, Columns: columns}
+	seen := make(map[string]bool)
+	for _, column := range columns {
+		seen[column] = true
+	}
+
+	for _, line := range content.Lines {
+		if strings.TrimSpace(line.Content) == "" {
+			continue
+		}
+
+		keys, fields, ok := parse(line.Content)
+		if !ok {
+			records.Unparsed++
+			continue
+		}
+
+		for _, key := range keys {
+			if !seen[key] {
+				seen[key] = true
+				records.Columns = append(records.Columns, key)
+			}
+		}
+
+		record := newRecord(line)
+		record.Fields = fields
+		records.Rows = append(records.Rows, record)
+	}
+
+	return records
+}
+
+func newRecord(line reader.Line) reader.Record {
+	return reader.Record{Line: line.Number, Offset: line.Offset, Fields: make(map[string]any)}
+}
+
+func splitDelimited(line string, comma rune) ([]string, bool) {
+	r := csv.NewReader(strings.NewReader(line))
+	r.Comma = comma
+	r.LazyQuotes = true
+	r.FieldsPerRecord = -1
+
+	fields, err := r.Read()
+	if err != nil {
+		return nil, false
+	}
+	return fields, true
+}
+
+// parseJSON reads a JSON object, flattening nested objects into dotted keys
+// so each leaf gets its own column.
+func parseJSON(line string) ([]string, map[string]any, bool) {
+	var object map[string]any
+	if err := decodeJSON(line, &object); err != nil {
+		return nil, nil, false
+	}
+
+	keys, err := objectKeys(line)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	fields := make(map[string]any)
+	var flatKeys []string
+	for _, key := range keys {
+		flatKeys = flatten(key, object[key], fields, flatKeys)
+	}
+	return flatKeys, fields, true
+}
+
+func flatten(prefix string, value any, fields map[string]any, keys []string) []string {
+	nested, ok := value.(map[string]any)
+	if !ok || len(nested) == 0 {
+		fields[prefix] = value
+		return append(keys, prefix)
+	}
+
+	for _, key := range sortedKeys(nested) {
+		keys = flatten(prefix+"."+key, nested[key], fields, keys)
+	}
+	return keys
+}
+
+func parseApache(line string) ([]string, map[string]any, bool) {
+	match := apacheLine.FindStringSubmatch(line)
+	if match == nil {
+		return nil, nil, false
+	}
+
+	fields := make(map[string]any)
+	for i, column := range apacheColumns {
+		// the common format has no referer or user agent
+		if match[i+1] != "" || i < 7 {
+			fields[column] = match[i+1]
+		}
+	}
+	return nil, fields, true
+}
+
+// parseLogfmt reads key=value pairs where values may be double quoted. A
+// key without a value is taken as true.
+func parseLogfmt(line string) ([]string, map[string]any, bool) {
+	var keys []string
+	fields := make(map[string]any)
+
+	i := 0
+	for i < len(line) {
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+		if i == len(line) {
+			break
+		}
+
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' {
+			i++
+		}
+		key := line[start:i]
+		if key == "" {
+			return nil, nil, false
+		}
+
+		var value any = true
+		if i < len(line) && line[i] == '=' {
+			i++
+			if i < len(line) && line[i] == '"' {
+				end := i + 1
+				for end < len(line) && line[end] != '"' {
+					if line[end] == '\\' {
+						end++
+					}
+					end++
+				}
+				if end >= len(line) {
+					return nil, nil, false
+				}
+				unquoted, err := strconv.Unquote(line[i : end+1])
+				if err != nil {
+					return nil, nil, false
+				}
+				value = unquoted
+				i = end + 1
+			} else {
+				start := i
+				for i < len(line) && line[i] != ' ' {
+					i++
+				}
+				value = line[start:i]
+			}
+		}
+
+		if _, ok := fields[key]; !ok {
+			keys = append(keys, key)
+		}
+		fields[key] = value
+	}
+
+	return keys, fields, len(keys) > 0
+}
+
+func isJSONObject(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "{") && json.Valid([]byte(line))
+}
+
+// isLogfmt wants at least two key=value pairs so ordinary prose with an
+// equals sign is not mistaken for it.
+func isLogfmt(line string) bool {
+	keys, fields, ok := parseLogfmt(line)
+	if !ok {
+		return false
+	}
+
+	pairs := 0
+	for _, key := range keys {
+		if _, bare := fields[key].(bool); !bare {
+			pairs++
+		}
+	}
+	return pairs >= 2 && pairs*2 >= len(keys)
+}
+
+// consistentFields reports whether every line splits into the same number
+// of at least two fields.
+func consistentFields(lines []string, comma rune) bool {
+	count := -1
+	for _, line := range lines {
+		fields, ok := splitDelimited(line, comma)
+		if !ok || len(fields) < 2 {
+			return false
+		}
+		if count >= 0 && len(fields) != count {
+			return false
+		}
+		count = len(fields)
+	}
+	return true
+}
+
+func all(lines []string, fn func(string) bool) bool {
+	for _, line := range lines {
+		if !fn(line) {
+			return false
+		}
+	}
+	return true
+}
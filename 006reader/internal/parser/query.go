@@ -0,0 +1,190 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/samnart1/GoLang/006reader/internal/reader"
+)
+
+// Query narrows parsed records down: filters drop rows, sort keys order
+// them and columns pick which fields are shown, in that order.
+type Query struct {
+	Columns	[]string
+	// Sort keys are column names, prefixed with - to sort descending
+	Sort	[]string
+	Filters	[]Filter
+}
+
+// Filter compares one column of each row against a value.
+type Filter struct {
+	Column	string
+	Op		string
+	Value	string
+	re		*regexp.Regexp
+}
+
+// filterOps is ordered so longer operators are tried first.
+var filterOps = []string{"!=", ">=", "<=", "!~", "=", "~", ">", "<"}
+
+// ParseFilter reads a filter such as status>=500, level=error or
+// msg~timeout. ~ and !~ match a regular expression.
+func ParseFilter(expr string) (Filter, error) {
+	end := strings.IndexAny(expr, "=!~<>")
+	if end <= 0 {
+		return Filter{}, fmt.Errorf("invalid filter %q: expected column, operator and value", expr)
+	}
+
+	for _, op := range filterOps {
+		if !strings.HasPrefix(expr[end:], op) {
+			continue
+		}
+
+		filter := Filter{Column: expr[:end], Op: op, Value: expr[end+len(op):]}
+		if op == "~" || op == "!~" {
+			re, err := regexp.Compile(filter.Value)
+			if err != nil {
+				return Filter{}, fmt.Errorf("invalid filter %q: %w", expr, err)
+			}
+			filter.re = re
+		}
+		return filter, nil
+	}
+
+	return Filter{}, fmt.Errorf("invalid filter %q: unknown operator", expr)
+}
+
+// Match reports whether a row passes the filter. Ordering operators compare
+// numerically when both sides are numbers.
+func (f Filter) Match(record reader.Record) bool {
+	value := record.Text(f.Column)
+
+	switch f.Op {
+	case "=":
+		return value == f.Value
+	case "!=":
+		return value != f.Value
+	case "~":
+		return f.re.MatchString(value)
+	case "!~":
+		return !f.re.MatchString(value)
+	}
+
+	if _, ok := record.Fields[f.Column]; !ok {
+		return false
+	}
+
+	cmp := compare(value, f.Value)
+	switch f.Op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp <= 0
+	}
+}
+
+// Apply filters, sorts and narrows the records in place.
+func (q *Query) Apply(records *reader.Records) error {
+	known := make(map[string]bool)
+	for _, column := range records.Columns {
+		known[column] = true
+	}
+
+	check := func(column string) error {
+		if !known[column] {
+			return fmt.Errorf("unknown column %q (available: %s)", column, strings.Join(records.Columns, ", "))
+		}
+		return nil
+	}
+
+	for _, filter := range q.Filters {
+		if err := check(filter.Column); err != nil {
+			return err
+		}
+	}
+	for _, key := range q.Sort {
+		if err := check(strings.TrimPrefix(key, "-")); err != nil {
+			return err
+		}
+	}
+	for _, column := range q.Columns {
+		if err := check(column); err != nil {
+			return err
+		}
+	}
+
+	if len(q.Filters) > 0 {
+		rows := records.Rows[:0]
+		for _, row := range records.Rows {
+			if q.matches(row) {
+				rows = append(rows, row)
+			}
+		}
+		records.Rows = rows
+	}
+
+	if len(q.Sort) > 0 {
+		sort.SliceStable(records.Rows, func(i, j int) bool {
+			for _, key := range q.Sort {
+				column := strings.TrimPrefix(key, "-")
+				cmp := compare(records.Rows[i].Text(column), records.Rows[j].Text(column))
+				if cmp == 0 {
+					continue
+				}
+				if strings.HasPrefix(key, "-") {
+					return cmp > 0
+				}
+				return cmp < 0
+			}
+			return false
+		})
+	}
+
+	if len(q.Columns) > 0 {
+		records.Columns = q.Columns
+		for i, row := range records.Rows {
+			fields := make(map[string]any, len(q.Columns))
+			for _, column := range q.Columns {
+				if value, ok := row.Fields[column]; ok {
+					fields[column] = value
+				}
+			}
+			records.Rows[i].Fields = fields
+		}
+	}
+
+	return nil
+}
+
+func (q *Query) matches(record reader.Record) bool {
+	for _, filter := range q.Filters {
+		if !filter.Match(record) {
+			return false
+		}
+	}
+	return true
+}
+
+// compare orders two values numerically when both are numbers and as text
+// otherwise.
+func compare(a, b string) int {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
\ No newline at end of file
@@ -0,0 +1,316 @@
+package reader
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// sampleSize is how much of the start of a file is looked at to guess its
+// encoding.
+const sampleSize = 64 * 1024
+
+// Charset is a text encoding that lines are transcoded from into UTF-8.
+type Charset struct {
+	Name		string
+	// BOMLength is the size of the byte order mark the file starts with
+	BOMLength	int
+	encoding	encoding.Encoding
+	// width is the size of a code unit, 2 for UTF-16
+	width		int
+	bigEndian	bool
+}
+
+var (
+	bomUTF8		= []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE	= []byte{0xFF, 0xFE}
+	bomUTF16BE	= []byte{0xFE, 0xFF}
+)
+
+func utf8Charset() *Charset {
+	return &Charset{Name: "utf-8", width: 1}
+}
+
+func utf16Charset(bigEndian bool) *Charset {
+	if bigEndian {
+		return &Charset{Name: "utf-16be", encoding: unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), width: 2, bigEndian: true}
+	}
+	return &Charset{Name: "utf-16le", encoding: unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), width: 2}
+}
+
+// LookupCharset returns the charset with the given name. Besides the
+// encodings DetectCharset recognises, any IANA registered single or
+// multi-byte encoding supported by x/text can be named.
+func LookupCharset(name string) (*Charset, error) {
+	key := strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(name))
+
+	switch key {
+	case "utf8":
+		return utf8Charset(), nil
+	case "utf16le", "utf16":
+		return utf16Charset(false), nil
+	case "utf16be":
+		return utf16Charset(true), nil
+	case "iso88591", "latin1", "l1":
+		return &Charset{Name: "iso-8859-1", encoding: charmap.ISO8859_1, width: 1}, nil
+	case "windows1252", "cp1252":
+		return &Charset{Name: "windows-1252", encoding: charmap.Windows1252, width: 1}, nil
+	case "shiftjis", "sjis", "cp932", "windows31j":
+		return &Charset{Name: "shift_jis", encoding: japanese.ShiftJIS, width: 1}, nil
+	}
+
+	enc, err := ianaindex.IANA.Encoding(name)
+	if err != nil || enc == nil {
+		return nil, fmt.Errorf("unsupported encoding: %s", name)
+	}
+	canonical, err := ianaindex.IANA.Name(enc)
+	if err != nil {
+		canonical = strings.ToLower(name)
+	}
+	if strings.HasPrefix(strings.ToLower(canonical), "utf-16") || strings.HasPrefix(strings.ToLower(canonical), "utf-32") {
+		return nil, fmt.Errorf("unsupported encoding: %s", name)
+	}
+
+	return &Charset{Name: strings.ToLower(canonical), encoding: enc, width: 1}, nil
+}
+
+// ResolveCharset picks the charset for a file. A name of "auto" or "" detects
+// it from the start of the file, anything else is looked up and only the
+// byte order mark is checked.
+func ResolveCharset(file io.ReaderAt, name string) (*Charset, error) {
+	sample := make([]byte, sampleSize)
+	n, err := file.ReadAt(sample, 0)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read sample: %w", err)
+	}
+	return resolveCharset(sample[:n], name)
+}
+
+func resolveCharset(sample []byte, name string) (*Charset, error) {
+	if name == "" || strings.EqualFold(name, "auto") {
+		return DetectCharset(sample), nil
+	}
+
+	charset, err := LookupCharset(name)
+	if err != nil {
+		return nil, err
+	}
+	if bom := charset.bom(); bom != nil && bytes.HasPrefix(sample, bom) {
+		charset.BOMLength = len(bom)
+	}
+	return charset, nil
+}
+
+// DetectCharset guesses the encoding of sample, the first bytes of a file.
+// A byte order mark wins; otherwise UTF-16 is recognised by its zero bytes,
+// valid UTF-8 is taken as is, and the remaining bytes decide between
+// Shift-JIS and the Latin code pages.
+func DetectCharset(sample []byte) *Charset {
+	switch {
+	case bytes.HasPrefix(sample, bomUTF8):
+		charset := utf8Charset()
+		charset.BOMLength = len(bomUTF8)
+		return charset
+	case bytes.HasPrefix(sample, bomUTF16LE):
+		charset := utf16Charset(false)
+		charset.BOMLength = len(bomUTF16LE)
+		return charset
+	case bytes.HasPrefix(sample, bomUTF16BE):
+		charset := utf16Charset(true)
+		charset.BOMLength = len(bomUTF16BE)
+		return charset
+	}
+
+	if charset := detectUTF16(sample); charset != nil {
+		return charset
+	}
+
+	if utf8.Valid(trimPartialRune(sample)) {
+		return utf8Charset()
+	}
+
+	if looksShiftJIS(sample) {
+		charset, _ := LookupCharset("shift_jis")
+		return charset
+	}
+
+	// 0x80-0x9F are control codes in Latin-1 but printable in Windows-1252
+	for _, b := range sample {
+		if b >= 0x80 && b <= 0x9F {
+			charset, _ := LookupCharset("windows-1252")
+			return charset
+		}
+	}
+
+	charset, _ := LookupCharset("iso-8859-1")
+	return charset
+}
+
+// detectUTF16 spots BOM-less UTF-16, where mostly-ASCII text leaves a zero
+// in every other byte.
+func detectUTF16(sample []byte) *Charset {
+	pairs := len(sample) / 2
+	if pairs < 2 {
+		return nil
+	}
+
+	var evenZeros, oddZeros int
+	for i := 0; i < pairs*2; i += 2 {
+		if sample[i] == 0 {
+			evenZeros++
+		}
+		if sample[i+1] == 0 {
+			oddZeros++
+		}
+	}
+
+	switch {
+	case oddZeros*10 >= pairs*3 && evenZeros*20 < pairs:
+		return utf16Charset(false)
+	case evenZeros*10 >= pairs*3 && oddZeros*20 < pairs:
+		return utf16Charset(true)
+	}
+	return nil
+}
+
+// trimPartialRune drops a multi-byte sequence cut off by the end of the
+// sample so it does not count against UTF-8.
+func trimPartialRune(sample []byte) []byte {
+	for i := len(sample) - 1; i >= 0 && i >= len(sample)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(sample[i]) {
+			if !utf8.FullRune(sample[i:]) {
+				return sample[:i]
+			}
+			break
+		}
+	}
+	return sample
+}
+
+// looksShiftJIS reports whether every non-ASCII byte forms a valid Shift-JIS
+// character and most double-byte characters use the lead bytes of kana and
+// common kanji, which Latin text almost never produces. The byte ranges alone
+// admit plenty of random data, so the sample must also decode to assigned
+// characters.
+func looksShiftJIS(sample []byte) bool {
+	if !shiftJISRanges(sample) {
+		return false
+	}
+
+	decoded, err := japanese.ShiftJIS.NewDecoder().Bytes(trimPartialShiftJIS(sample))
+	if err != nil || bytes.ContainsRune(decoded, utf8.RuneError) {
+		return false
+	}
+
+	// nor does text carry control characters other than whitespace
+	for _, r := range string(decoded) {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' || r == 0x7F {
+			return false
+		}
+	}
+	return true
+}
+
+// trimPartialShiftJIS drops a lead byte cut off by the end of the sample.
+func trimPartialShiftJIS(sample []byte) []byte {
+	for i := 0; i < len(sample); i++ {
+		if b := sample[i]; b >= 0x81 && b <= 0x9F || b >= 0xE0 && b <= 0xFC {
+			if i+1 == len(sample) {
+				return sample[:i]
+			}
+			i++
+		}
+	}
+	return sample
+}
+
+func shiftJISRanges(sample []byte) bool {
+	var pairs, common int
+
+	for i := 0; i < len(sample); i++ {
+		b := sample[i]
+		switch {
+		case b < 0x80, b >= 0xA1 && b <= 0xDF:
+			// ASCII and half-width katakana
+		case b >= 0x81 && b <= 0x9F, b >= 0xE0 && b <= 0xFC:
+			if i+1 == len(sample) {
+				// cut off by the end of the sample
+				break
+			}
+			trail := sample[i+1]
+			if trail < 0x40 || trail == 0x7F || trail > 0xFC {
+				return false
+			}
+			pairs++
+			if b <= 0x9F {
+				common++
+			}
+			i++
+		default:
+			return false
+		}
+	}
+
+	return pairs > 0 && common*2 >= pairs
+}
+
+func (c *Charset) bom() []byte {
+	switch {
+	case c.width == 2 && c.bigEndian:
+		return bomUTF16BE
+	case c.width == 2:
+		return bomUTF16LE
+	case c.encoding == nil:
+		return bomUTF8
+	}
+	return nil
+}
+
+// unit encodes an ASCII character as one code unit of the charset.
+func (c *Charset) unit(ch byte) []byte {
+	switch {
+	case c.width == 2 && c.bigEndian:
+		return []byte{0, ch}
+	case c.width == 2:
+		return []byte{ch, 0}
+	}
+	return []byte{ch}
+}
+
+// trimLineEnding strips a trailing \n or \r\n in the charset's code units.
+func (c *Charset) trimLineEnding(line []byte) []byte {
+	if nl := c.unit('\n'); bytes.HasSuffix(line, nl) {
+		line = line[:len(line)-len(nl)]
+		if cr := c.unit('\r'); bytes.HasSuffix(line, cr) {
+			line = line[:len(line)-len(cr)]
+		}
+	}
+	return line
+}
+
+// decode transcodes one line into UTF-8. UTF-8 input is passed through.
+func (c *Charset) decode(decoder *encoding.Decoder, line []byte) string {
+	if decoder == nil {
+		return string(line)
+	}
+	decoded, err := decoder.Bytes(line)
+	if err != nil {
+		return string(line)
+	}
+	return string(decoded)
+}
+
+func (c *Charset) newDecoder() *encoding.Decoder {
+	if c.encoding == nil {
+		return nil
+	}
+	return c.encoding.NewDecoder()
+}
\ No newline at end of file
@@ -0,0 +1,170 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"golang.org/x/text/encoding"
+)
+
+// LineScanner splits a reader into lines of any length while tracking the
+// byte offset of each one. Unlike bufio.Scanner it never fails on long lines.
+// Lines are split on the charset's own newline code unit and returned as
+// UTF-8, so offsets always refer to the raw file.
+type LineScanner struct {
+	r		*bufio.Reader
+	charset	*Charset
+	decoder	*encoding.Decoder
+	// offset is where the next line starts
+	offset	int64
+	// ending is the line ending of the last line read
+	ending	string
+}
+
+// NewLineScanner reads lines from r, which is positioned at offset. A nil
+// charset reads UTF-8.
+func NewLineScanner(r io.Reader, offset int64, bufferSize int, charset *Charset) *LineScanner {
+	if bufferSize < 16 {
+		bufferSize = 16
+	}
+	if charset == nil {
+		charset = utf8Charset()
+	}
+	return &LineScanner{
+		r: bufio.NewReaderSize(r, bufferSize),
+		charset: charset,
+		decoder: charset.newDecoder(),
+		offset: offset,
+	}
+}
+
+// Next returns the next line without its line ending and the offset it
+// started at. The last line does not need a trailing newline.
+func (s *LineScanner) Next() (string, int64, error) {
+	start := s.offset
+
+	var line []byte
+	for {
+		chunk, err := s.r.ReadSlice('\n')
+		line = append(line, chunk...)
+
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			if err != io.EOF || len(line) == 0 {
+				return "", start, err
+			}
+			break
+		}
+
+		done, err := s.newlineComplete(&line)
+		if err != nil {
+			return "", start, err
+		}
+		if done {
+			break
+		}
+	}
+
+	s.offset += int64(len(line))
+	trimmed := s.charset.trimLineEnding(line)
+	switch (len(line) - len(trimmed)) / s.charset.width {
+	case 0:
+		s.ending = ""
+	case 1:
+		s.ending = EndingLF
+	default:
+		s.ending = EndingCRLF
+	}
+	return s.charset.decode(s.decoder, trimmed), start, nil
+}
+
+// Ending is the line ending of the line last returned by Next: EndingLF,
+// EndingCRLF, or "" for a last line without one.
+func (s *LineScanner) Ending() string {
+	return s.ending
+}
+
+// newlineComplete decides whether the 0x0A byte that ends line really ends
+// it. In UTF-16 it must be the low byte of an aligned code unit whose other
+// byte is zero; anything else is part of a different character.
+func (s *LineScanner) newlineComplete(line *[]byte) (bool, error) {
+	if s.charset.width == 1 {
+		return true, nil
+	}
+
+	n := len(*line)
+	if s.charset.bigEndian {
+		return n%2 == 0 && (*line)[n-2] == 0, nil
+	}
+	if n%2 == 0 {
+		return false, nil
+	}
+
+	b, err := s.r.ReadByte()
+	if err == io.EOF {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	*line = append(*line, b)
+	return b == 0, nil
+}
+
+// Offset is where the next line starts.
+func (s *LineScanner) Offset() int64 {
+	return s.offset
+}
+
+// tailOffset returns where the last n lines of the file start, reading
+// backwards in chunks so only the end of the file is touched.
+func tailOffset(f io.ReaderAt, size int64, n, chunkSize int, charset *Charset) (int64, error) {
+	width := charset.width
+	if chunkSize < width {
+		chunkSize = 8192
+	}
+	// keep every chunk aligned to whole code units
+	chunkSize -= chunkSize % width
+
+	newline := charset.unit('\n')
+	buf := make([]byte, chunkSize)
+	pos := size - size%int64(width)
+	found := 0
+	// a newline at the very end closes the last line, it does not start one
+	last := true
+
+	for pos > 0 {
+		readSize := int64(chunkSize)
+		if pos < readSize {
+			readSize = pos
+		}
+		pos -= readSize
+
+		if _, err := f.ReadAt(buf[:readSize], pos); err != nil && err != io.EOF {
+			return 0, err
+		}
+
+		for i := readSize - int64(width); i >= 0; i -= int64(width) {
+			isNewline := bytes.Equal(buf[i:i+int64(width)], newline)
+			if last {
+				last = false
+				if isNewline {
+					continue
+				}
+			}
+			if !isNewline {
+				continue
+			}
+
+			found++
+			if found == n {
+				return pos + i + int64(width), nil
+			}
+		}
+	}
+
+	return 0, nil
+}
\ No newline at end of file
@@ -0,0 +1,450 @@
+package reader
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// MultiWatchConfig describes what to follow. Paths may be files, directories,
+// which are watched recursively, or glob patterns where ** matches any number
+// of directories. The remaining settings apply to every file.
+type MultiWatchConfig struct {
+	Paths         []string
+	TailMode      bool
+	PollInterval  int
+	BufferSize    int
+	Encoding      string
+	Poll          bool
+	FinishRotated bool
+}
+
+// MultiWatcher follows many files at once like tail -F, multiplexing their
+// events into one stream. Files that appear later under a watched directory
+// or matching a pattern are picked up and read from the start.
+type MultiWatcher struct {
+	config  *MultiWatchConfig
+	targets []watchTarget
+	watcher *fsnotify.Watcher
+	events  chan WatchEvent
+	errors  chan error
+	done    chan bool
+	polling bool
+
+	started []string
+
+	// only touched by the run goroutine once started
+	followers map[string]*Watcher
+	dirs      map[string]bool
+}
+
+// watchTarget is one path argument: the directory to watch for it and which
+// files under that directory belong to it.
+type watchTarget struct {
+	root      string
+	recursive bool
+	match     func(path string) bool
+}
+
+func NewMultiWatcher(config *MultiWatchConfig) *MultiWatcher {
+	return &MultiWatcher{
+		config:    config,
+		events:    make(chan WatchEvent, 100),
+		errors:    make(chan error, 10),
+		done:      make(chan bool),
+		followers: make(map[string]*Watcher),
+		dirs:      make(map[string]bool),
+	}
+}
+
+func (m *MultiWatcher) Start() error {
+	for _, path := range m.config.Paths {
+		target, err := newWatchTarget(path)
+		if err != nil {
+			return err
+		}
+		m.targets = append(m.targets, target)
+	}
+
+	if !m.config.Poll {
+		if watcher, err := fsnotify.NewWatcher(); err == nil {
+			m.watcher = watcher
+		}
+	}
+	m.polling = m.watcher == nil
+
+	existing, err := m.discover()
+	if err != nil {
+		m.closeWatcher()
+		return err
+	}
+	if m.polling && !m.config.Poll {
+		// fsnotify failed on one of the directories
+		m.closeWatcher()
+	}
+
+	for _, path := range existing {
+		if err := m.follow(path, m.config.TailMode); err != nil {
+			m.closeFollowers()
+			m.closeWatcher()
+			return err
+		}
+	}
+	m.started = existing
+
+	go m.run()
+
+	return nil
+}
+
+// newWatchTarget works out what to watch for one path argument.
+func newWatchTarget(path string) (watchTarget, error) {
+	path = filepath.Clean(path)
+
+	if !strings.ContainsAny(path, "*?[") {
+		info, err := os.Stat(path)
+		if err != nil {
+			return watchTarget{}, fmt.Errorf("cannot watch %s: %w", path, err)
+		}
+
+		if info.IsDir() {
+			return watchTarget{
+				root:      path,
+				recursive: true,
+				match:     func(string) bool { return true },
+			}, nil
+		}
+
+		return watchTarget{
+			root:  filepath.Dir(path),
+			match: func(name string) bool { return name == path },
+		}, nil
+	}
+
+	if _, err := filepath.Match(path, ""); err != nil {
+		return watchTarget{}, fmt.Errorf("invalid pattern %s: %w", path, err)
+	}
+
+	// watch the deepest directory the pattern is fixed to
+	segments := strings.Split(path, string(filepath.Separator))
+	fixed := 0
+	for fixed < len(segments) && !strings.ContainsAny(segments[fixed], "*?[") {
+		fixed++
+	}
+
+	root := strings.Join(segments[:fixed], string(filepath.Separator))
+	switch {
+	case root == "" && strings.HasPrefix(path, string(filepath.Separator)):
+		root = string(filepath.Separator)
+	case root == "":
+		root = "."
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return watchTarget{}, fmt.Errorf("cannot watch %s: %s is not a directory", path, root)
+	}
+
+	pattern := segments[fixed:]
+	return watchTarget{
+		root:      root,
+		recursive: len(pattern) > 1,
+		match: func(name string) bool {
+			rel, err := filepath.Rel(root, name)
+			if err != nil {
+				return false
+			}
+			return matchSegments(pattern, strings.Split(rel, string(filepath.Separator)))
+		},
+	}, nil
+}
+
+// matchSegments matches a path against a pattern one directory at a time,
+// letting a ** segment stand for any number of directories.
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+	if ok, _ := filepath.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], name[1:])
+}
+
+// discover walks the targets, watching their directories, and returns the
+// matching files that are not followed yet.
+func (m *MultiWatcher) discover() ([]string, error) {
+	var found []string
+	seen := make(map[string]bool)
+
+	for _, target := range m.targets {
+		err := filepath.WalkDir(target.root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				// directories can vanish while walking
+				if path == target.root {
+					return err
+				}
+				return nil
+			}
+
+			if entry.IsDir() {
+				if path != target.root && !target.recursive {
+					return filepath.SkipDir
+				}
+				m.watchDir(path)
+				return nil
+			}
+
+			if entry.Type().IsRegular() && target.match(path) && m.followers[path] == nil && !seen[path] {
+				seen[path] = true
+				found = append(found, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", target.root, err)
+		}
+	}
+
+	sort.Strings(found)
+	return found, nil
+}
+
+func (m *MultiWatcher) watchDir(dir string) {
+	if m.polling || m.dirs[dir] {
+		return
+	}
+	if err := m.watcher.Add(dir); err != nil {
+		// fall back to polling everything rather than missing files
+		m.polling = true
+		return
+	}
+	m.dirs[dir] = true
+}
+
+// follow starts following a file, from its end in tail mode.
+func (m *MultiWatcher) follow(path string, tail bool) error {
+	follower := newFollower(&WatchConfig{
+		FilePath:      path,
+		TailMode:      tail,
+		PollInterval:  m.config.PollInterval,
+		BufferSize:    m.config.BufferSize,
+		Encoding:      m.config.Encoding,
+		FinishRotated: m.config.FinishRotated,
+	}, m.events, m.errors, m.done)
+
+	if err := follower.open(); err != nil {
+		return err
+	}
+
+	if tail {
+		info, err := follower.file.Stat()
+		if err == nil {
+			err = follower.skipTo(info.Size())
+		}
+		if err != nil {
+			follower.file.Close()
+			return err
+		}
+	}
+
+	m.followers[path] = follower
+	return nil
+}
+
+// Polling reports whether files are checked on a timer because fsnotify was
+// unavailable or disabled when the watcher started.
+func (m *MultiWatcher) Polling() bool {
+	return m.polling
+}
+
+// Files lists the files that were found when the watcher started.
+func (m *MultiWatcher) Files() []string {
+	return m.started
+}
+
+func (m *MultiWatcher) paths() []string {
+	files := make([]string, 0, len(m.followers))
+	for path := range m.followers {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func (m *MultiWatcher) Stop() {
+	m.closeWatcher()
+	close(m.done)
+}
+
+func (m *MultiWatcher) Events() <-chan WatchEvent {
+	return m.events
+}
+
+func (m *MultiWatcher) Errors() <-chan error {
+	return m.errors
+}
+
+func (m *MultiWatcher) run() {
+	defer m.closeFollowers()
+
+	if !m.config.TailMode {
+		for _, path := range m.paths() {
+			m.followers[path].readAppended("initial")
+		}
+	}
+
+	if m.polling {
+		m.poll()
+		return
+	}
+
+	for {
+		select {
+		case event, ok := <-m.watcher.Events:
+			if !ok {
+				return
+			}
+			m.handle(event)
+			if m.polling {
+				// a new directory could not be watched
+				m.poll()
+				return
+			}
+
+		case err, ok := <-m.watcher.Errors:
+			if !ok {
+				return
+			}
+			m.sendError(err)
+
+		case <-m.done:
+			return
+		}
+	}
+}
+
+func (m *MultiWatcher) handle(event fsnotify.Event) {
+	path := filepath.Clean(event.Name)
+
+	if follower := m.followers[path]; follower != nil {
+		follower.check()
+		return
+	}
+
+	if !event.Has(fsnotify.Create) {
+		return
+	}
+
+	// a new directory may already hold files by the time it is watched, so
+	// rescan rather than waiting for events from it
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		m.pickUp()
+		return
+	}
+
+	for _, target := range m.targets {
+		if target.match(path) && m.inTarget(target, path) {
+			m.add(path)
+			return
+		}
+	}
+}
+
+// inTarget reports whether path is somewhere the target watches.
+func (m *MultiWatcher) inTarget(target watchTarget, path string) bool {
+	dir := filepath.Dir(path)
+	if dir == target.root {
+		return true
+	}
+	rel, err := filepath.Rel(target.root, dir)
+	return target.recursive && err == nil && !strings.HasPrefix(rel, "..")
+}
+
+func (m *MultiWatcher) poll() {
+	interval := time.Duration(m.config.PollInterval) * time.Millisecond
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.pickUp()
+			for _, path := range m.paths() {
+				m.followers[path].check()
+			}
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// pickUp follows files that appeared since the last scan.
+func (m *MultiWatcher) pickUp() {
+	found, err := m.discover()
+	if err != nil {
+		m.sendError(err)
+		return
+	}
+	for _, path := range found {
+		m.add(path)
+	}
+}
+
+// add follows a file created while watching, reading it from the start.
+func (m *MultiWatcher) add(path string) {
+	if err := m.follow(path, false); err != nil {
+		m.sendError(err)
+		return
+	}
+
+	follower := m.followers[path]
+	follower.send(WatchEvent{
+		Type:    "created",
+		Content: "Now watching new file",
+		Time:    time.Now(),
+	})
+	follower.readAppended("new_line")
+}
+
+func (m *MultiWatcher) sendError(err error) {
+	select {
+	case m.errors <- err:
+	case <-m.done:
+	}
+}
+
+func (m *MultiWatcher) closeWatcher() {
+	if m.watcher != nil {
+		m.watcher.Close()
+	}
+}
+
+func (m *MultiWatcher) closeFollowers() {
+	for _, follower := range m.followers {
+		if follower.file != nil {
+			follower.file.Close()
+		}
+	}
+}
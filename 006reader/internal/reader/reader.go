@@ -6,36 +6,122 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 )
 
 type Content struct {
 	Lines		[]Line		`json:"lines"`
 	Metadata	Metadata	`json:"metadata"`
+	// Records is set when the lines were parsed as a structured format
+	Records		*Records	`json:"records,omitempty"`
+	// Data holds the bytes read in raw mode, where there are no lines
+	Data		[]byte		`json:"-"`
 }
 
 type Line struct {
-	Number		int		`json:"number"`
+	// Number is zero when reading started mid-file, where earlier lines
+	// were never counted
+	Number		int		`json:"number,omitempty"`
+	Offset		int64	`json:"offset"`
 	Content		string	`json:"content"`
+	// Matches are set on lines selected by a search
+	Matches		[]Span	`json:"matches,omitempty"`
+	// Context marks lines shown only as context around a match
+	Context		bool	`json:"context,omitempty"`
+	// Ending is the line ending the line had in the file
+	Ending		string	`json:"-"`
 }
 
+const (
+	EndingLF	= "lf"
+	EndingCRLF	= "crlf"
+)
+
 type Metadata struct {
-	FilePath	string			`json:"file_path"`
-	FileName	string			`json:"file_name"`
-	Size		int64			`json:"size"`
-	ModTime		time.Time		`json:"mod_time"`
-	LineCount	int				`json:"line_count"`
-	Encoding	string			`json:"encoding"`
-	ReadTime	time.Duration	`json:"read_time"`
+	FilePath			string			`json:"file_path"`
+	FileName			string			`json:"file_name"`
+	Size				int64			`json:"size"`
+	ModTime				time.Time		`json:"mod_time"`
+	LineCount			int				`json:"line_count"`
+	StartOffset			int64			`json:"start_offset"`
+	EndOffset			int64			`json:"end_offset"`
+	Encoding			string			`json:"encoding"`
+	BOM					bool			`json:"bom,omitempty"`
+	Search				*SearchResult	`json:"search,omitempty"`
+	// Compression names the codec the file was read through, if any
+	Compression			string			`json:"compression,omitempty"`
+	// Archive and Member are set when reading a file inside a zip or tar
+	Archive				string			`json:"archive,omitempty"`
+	Member				string			`json:"member,omitempty"`
+	CompressedSize		int64			`json:"compressed_size,omitempty"`
+	UncompressedSize	int64			`json:"uncompressed_size,omitempty"`
+	ReadTime			time.Duration	`json:"read_time"`
+}
+
+// DataSize is the size of the text itself: the uncompressed size when the
+// file was compressed or archived, otherwise the file size.
+func (m *Metadata) DataSize() int64 {
+	if m.UncompressedSize > 0 {
+		return m.UncompressedSize
+	}
+	return m.Size
 }
 
 type Config struct {
 	FilePath	string
 	MaxLines	int
 	ShowLines	bool
+	// Encoding names the file's charset; "auto" or "" detects it
 	Encoding	string
 	BufferSize	int
+	// FromLine and ToLine select an inclusive, 1-based line range
+	FromLine	int
+	ToLine		int
+	// Offset and Length select a byte range; a Length of 0 reads to the end
+	Offset		int64
+	Length		int64
+	// Tail returns only the last Tail lines, seeking back from the end
+	Tail		int
+	// Search keeps only matching lines and their context
+	Search		*SearchConfig
+	// Raw reads the bytes between Offset and Length as they are, without
+	// decoding them or splitting them into lines
+	Raw			bool
+	// MaxSize caps the bytes decompressed from compressed files and archive
+	// members; 0 leaves them unlimited. Plain files are checked on disk.
+	MaxSize		int64
+}
+
+// Bounded reports whether the amount read is capped regardless of the file
+// size. A start position alone still reads through to the end.
+func (c *Config) Bounded() bool {
+	return c.ToLine > 0 || c.Length > 0 || c.Tail > 0
+}
+
+// Validate checks that the requested ranges make sense together.
+func (c *Config) Validate() error {
+	lines := c.FromLine > 0 || c.ToLine > 0
+	bytes := c.Offset > 0 || c.Length > 0
+
+	switch {
+	case c.FromLine < 0, c.ToLine < 0, c.Offset < 0, c.Length < 0, c.Tail < 0:
+		return fmt.Errorf("ranges cannot be negative")
+	case c.ToLine > 0 && c.ToLine < c.FromLine:
+		return fmt.Errorf("to-line %d is before from-line %d", c.ToLine, c.FromLine)
+	case lines && bytes, c.Tail > 0 && (lines || bytes):
+		return fmt.Errorf("line ranges, byte ranges and tail cannot be combined")
+	case c.Search != nil && (c.Search.Before < 0 || c.Search.After < 0 || c.Search.MaxCount < 0):
+		return fmt.Errorf("context and match counts cannot be negative")
+	case c.Raw && (lines || c.Tail > 0 || c.Search != nil):
+		return fmt.Errorf("raw reads only take a byte range")
+	}
+
+	if c.Search != nil {
+		if _, err := NewMatcher(c.Search); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type Reader struct {
@@ -55,99 +141,353 @@ func New(config *Config) *Reader {
 func (r *Reader) Read() (*Content, error) {
 	startTime := time.Now()
 
-	file, err := os.Open(r.config.FilePath)
+	src, err := openSource(r.config.FilePath, r.config.MaxSize)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, err
 	}
-	defer file.Close()
+	defer src.Close()
 
-	info, err := file.Stat()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get file info: %w", err)
+	content := &Content{
+		Metadata: Metadata{
+			FilePath: r.config.FilePath,
+		},
 	}
+	src.describe(&content.Metadata)
 
-	lines, err := r.readLines(file)
+	if r.config.Raw {
+		if err := r.raw(src, content); err != nil {
+			return nil, fmt.Errorf("failed to read bytes: %w", err)
+		}
+		content.Metadata.ReadTime = time.Since(startTime)
+		return content, nil
+	}
+
+	err = r.lines(src, &content.Metadata, true, func(line Line) error {
+		content.Lines = append(content.Lines, line)
+		return nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to read lines: %w", err)
 	}
 
-	content := &Content{
-		Lines: lines,
-		Metadata: Metadata{
-			FilePath: r.config.FilePath,
-			FileName: filepath.Base(r.config.FilePath),
-			Size: info.Size(),
-			ModTime: info.ModTime(),
-			LineCount: len(lines),
-			Encoding: r.config.FilePath,
-			ReadTime: time.Since(startTime),
-		},
-	}
+	content.Metadata.LineCount = len(content.Lines)
+	content.Metadata.ReadTime = time.Since(startTime)
 
 	return content, nil
 }
 
-func (r *Reader) readLines(file *os.File) ([]Line, error) {
-	var lines []Line
-	scanner := bufio.NewScanner(file)
-
-	buf := make([]byte, 0, r.config.BufferSize)
-	scanner.Buffer(buf, r.config.BufferSize*2)
+func (r *Reader) ReadStream(callback func(line Line) error) error {
+	src, err := openSource(r.config.FilePath, r.config.MaxSize)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
 
-	lineNum := 1
-	for scanner.Scan() {
-		if r.config.MaxLines > 0 && lineNum > r.config.MaxLines {
-			break
+	return r.lines(src, &Metadata{}, false, func(line Line) error {
+		if err := callback(line); err != nil {
+			return fmt.Errorf("callback error at offset %d: %w", line.Offset, err)
 		}
+		return nil
+	})
+}
+
+// lines resolves the charset of src and calls fn for every selected line.
+// Plain files are seeked; compressed data and archive members are streamed
+// from the start, and with measure set, read to the end to learn their size.
+func (r *Reader) lines(src *source, meta *Metadata, measure bool, fn func(Line) error) error {
+	if err := r.config.Validate(); err != nil {
+		return err
+	}
 
-		line := Line{
-			Number: lineNum,
-			Content: scanner.Text(),
+	if src.file != nil {
+		charset, err := ResolveCharset(src.file, r.config.Encoding)
+		if err != nil {
+			return err
 		}
-		lines = append(lines, line)
-		lineNum++
+		meta.Encoding, meta.BOM = charset.Name, charset.BOMLength > 0
+		return r.each(src.file, src.size, charset, meta, fn)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
+	counter := &countingReader{r: src.stream}
+	stream := bufio.NewReaderSize(counter, max(r.config.BufferSize, sampleSize))
+	sample, err := stream.Peek(sampleSize)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed to read sample: %w", err)
+	}
+
+	charset, err := resolveCharset(sample, r.config.Encoding)
+	if err != nil {
+		return err
 	}
+	meta.Encoding, meta.BOM = charset.Name, charset.BOMLength > 0
 
-	return lines, nil
+	if err := r.eachStream(stream, charset, meta, fn); err != nil {
+		return err
+	}
+
+	if measure && meta.UncompressedSize == 0 {
+		if _, err := io.Copy(io.Discard, stream); err != nil {
+			return fmt.Errorf("error reading file: %w", err)
+		}
+		meta.UncompressedSize = counter.n
+	}
+	return nil
 }
 
-func (r *Reader) ReadStream(callback func(line Line) error) error {
-	file, err := os.Open(r.config.FilePath)
+// raw reads the configured byte range into content.Data. Compressed data
+// and archive members are read from their decompressed bytes.
+func (r *Reader) raw(src *source, content *Content) error {
+	if err := r.config.Validate(); err != nil {
+		return err
+	}
+
+	var stream io.Reader
+	if src.file != nil {
+		if _, err := src.file.Seek(r.config.Offset, io.SeekStart); err != nil {
+			return fmt.Errorf("failed to seek to %d: %w", r.config.Offset, err)
+		}
+		stream = src.file
+	} else {
+		if _, err := io.CopyN(io.Discard, src.stream, r.config.Offset); err != nil && err != io.EOF {
+			return fmt.Errorf("failed to skip to %d: %w", r.config.Offset, err)
+		}
+		stream = src.stream
+	}
+	if r.config.Length > 0 {
+		stream = io.LimitReader(stream, r.config.Length)
+	}
+
+	data, err := io.ReadAll(stream)
 	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
+		return fmt.Errorf("error reading file: %w", err)
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	buf := make([]byte, 0, r.config.BufferSize)
-	scanner.Buffer(buf, r.config.BufferSize*2)
+	content.Data = data
+	content.Metadata.StartOffset = r.config.Offset
+	content.Metadata.EndOffset = r.config.Offset + int64(len(data))
+	if src.file == nil && r.config.Length == 0 && content.Metadata.UncompressedSize == 0 {
+		content.Metadata.UncompressedSize = content.Metadata.EndOffset
+	}
+	return nil
+}
+
+// each calls fn for every line selected by the config, holding only one line
+// in memory at a time and transcoding it from charset. The byte range the
+// lines came from and any search result are recorded in meta.
+func (r *Reader) each(file *os.File, size int64, charset *Charset, meta *Metadata, fn func(Line) error) error {
+	// byte ranges snap to whole code units and never start inside the BOM
+	start := r.config.Offset - r.config.Offset%int64(charset.width)
+	length := r.config.Length
+	if bom := int64(charset.BOMLength); start < bom {
+		if length > 0 {
+			if length -= bom - start; length <= 0 {
+				meta.StartOffset, meta.EndOffset = bom, bom
+				return nil
+			}
+		}
+		start = bom
+	}
+
+	if r.config.Tail > 0 {
+		offset, err := tailOffset(file, size, r.config.Tail, r.config.BufferSize, charset)
+		if err != nil {
+			return fmt.Errorf("failed to find tail: %w", err)
+		}
+		if start = offset; start < int64(charset.BOMLength) {
+			start = int64(charset.BOMLength)
+		}
+	}
+
+	if _, err := file.Seek(start, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek to %d: %w", start, err)
+	}
+
+	var src io.Reader = file
+	if length > 0 {
+		src = io.LimitReader(file, length)
+	}
+	scanner := NewLineScanner(src, start, r.config.BufferSize, charset)
+
+	// line numbers are only known when reading from the top
+	return r.scan(numbered(scanner, start == int64(charset.BOMLength)), start, meta, fn)
+}
+
+// eachStream is each for data that can only be read forwards. Offsets count
+// decompressed bytes, and a tail is found by keeping the last lines seen.
+func (r *Reader) eachStream(stream *bufio.Reader, charset *Charset, meta *Metadata, fn func(Line) error) error {
+	start := r.config.Offset - r.config.Offset%int64(charset.width)
+	length := r.config.Length
+	if bom := int64(charset.BOMLength); start < bom {
+		if length > 0 {
+			if length -= bom - start; length <= 0 {
+				meta.StartOffset, meta.EndOffset = bom, bom
+				return nil
+			}
+		}
+		start = bom
+	}
+
+	if _, err := io.CopyN(io.Discard, stream, start); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to skip to %d: %w", start, err)
+	}
+
+	var src io.Reader = stream
+	if length > 0 {
+		src = io.LimitReader(stream, length)
+	}
+	scanner := NewLineScanner(src, start, r.config.BufferSize, charset)
+	next := numbered(scanner, start == int64(charset.BOMLength))
+
+	if r.config.Tail > 0 {
+		ring, err := lastLines(next, r.config.Tail)
+		if err != nil {
+			return err
+		}
+		if len(ring) > 0 {
+			start = ring[0].line.Offset
+		}
+		next = func() (Line, int64, error) {
+			if len(ring) == 0 {
+				return Line{}, 0, io.EOF
+			}
+			entry := ring[0]
+			ring = ring[1:]
+			return entry.line, entry.end, nil
+		}
+	}
+
+	return r.scan(next, start, meta, fn)
+}
+
+type tailEntry struct {
+	line	Line
+	end		int64
+}
+
+// lastLines reads next to the end, keeping only the last n lines.
+func lastLines(next func() (Line, int64, error), n int) ([]tailEntry, error) {
+	ring := make([]tailEntry, 0, n)
+	for {
+		line, end, err := next()
+		if err == io.EOF {
+			return ring, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find tail: %w", err)
+		}
+		if len(ring) == n {
+			copy(ring, ring[1:])
+			ring = ring[:n-1]
+		}
+		ring = append(ring, tailEntry{line: line, end: end})
+	}
+}
+
+// numbered adapts a scanner to scan, counting lines from 1 when known is
+// set and leaving them unnumbered otherwise.
+func numbered(scanner *LineScanner, known bool) func() (Line, int64, error) {
+	lineNum := 0
+	if known {
+		lineNum = 1
+	}
+	return func() (Line, int64, error) {
+		text, offset, err := scanner.Next()
+		if err != nil {
+			return Line{}, 0, err
+		}
+		num := lineNum
+		if lineNum > 0 {
+			lineNum++
+		}
+		return Line{Number: num, Offset: offset, Content: text, Ending: scanner.Ending()}, scanner.Offset(), nil
+	}
+}
+
+// scan applies line ranges, the search and the line limit to the lines from
+// next, which returns each line and the offset just past it.
+func (r *Reader) scan(next func() (Line, int64, error), start int64, meta *Metadata, fn func(Line) error) error {
+	var search *searchState
+	if r.config.Search != nil {
+		var err error
+		if search, err = newSearchState(r.config.Search); err != nil {
+			return err
+		}
+		defer func() { meta.Search = search.result() }()
+	}
 
-	lineNum := 1
-	for scanner.Scan() {
-		if r.config.MaxLines > 0 && lineNum > r.config.MaxLines {
+	meta.StartOffset, meta.EndOffset = start, start
+	emitted := 0
+	emit := func(line Line) (bool, error) {
+		if r.config.MaxLines > 0 && emitted >= r.config.MaxLines {
+			return false, nil
+		}
+		if err := fn(line); err != nil {
+			return false, err
+		}
+		emitted++
+		return true, nil
+	}
+
+	for {
+		line, end, err := next()
+		if err == io.EOF {
 			break
 		}
+		if err != nil {
+			return fmt.Errorf("error reading file: %w", err)
+		}
 
-		line := Line{
-			Number: lineNum,
-			Content: scanner.Text(),
+		if r.config.FromLine > 0 && line.Number < r.config.FromLine {
+			meta.StartOffset = end
+			meta.EndOffset = meta.StartOffset
+			continue
+		}
+		if r.config.ToLine > 0 && line.Number > r.config.ToLine {
+			break
 		}
 
-		if err := callback(line); err != nil {
-			return fmt.Errorf("callback error at line %d: %w", lineNum, err)
+		lines := []Line{line}
+		if search != nil {
+			lines = search.feed(line)
+		}
+
+		more := true
+		for _, line := range lines {
+			if more, err = emit(line); err != nil {
+				return err
+			}
+			if !more {
+				break
+			}
+		}
+		if !more {
+			break
 		}
+		// the range read so far ends here even if the line was filtered out
+		meta.EndOffset = end
 
-		lineNum++
+		if search != nil && search.finished() {
+			break
+		}
 	}
-	return scanner.Err()
+
+	return nil
+}
+
+type countingReader struct {
+	r	io.Reader
+	n	int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
 }
 
 func GetFileInfo(filePath string) (*Metadata, error) {
-	info, err := os.Stat(filePath)
+	archivePath, _ := SplitArchivePath(filePath)
+	info, err := os.Stat(archivePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file info: %w", err)
 	}
@@ -161,6 +501,7 @@ func GetFileInfo(filePath string) (*Metadata, error) {
 }
 
 func ValidateFile(filePath string) error {
+	filePath, _ = SplitArchivePath(filePath)
 	info, err := os.Stat(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -183,6 +524,7 @@ func ValidateFile(filePath string) error {
 	return nil
 }
 
+// DetectEncoding guesses the charset of a file from its first bytes.
 func DetectEncoding(filePath string) (string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -190,23 +532,10 @@ func DetectEncoding(filePath string) (string, error) {
 	}
 	defer file.Close()
 
-	buffer := make([]byte, 1024)
-	n, err := file.Read(buffer)
-	if err != nil && err != io.EOF {
+	charset, err := ResolveCharset(file, "auto")
+	if err != nil {
 		return "", err
 	}
 
-	if n >= 3 && buffer[0] == 0xEF && buffer[1] == 0xBB && buffer[2] == 0xBF {
-		return "utf-8-bom", nil
-	}
-
-	if isValidUTF8(buffer[:n]) {
-		return "utf-8", nil
-	}
-
-	return "unknown", nil
-}
-
-func isValidUTF8(data []byte) bool {
-	return strings.ToValidUTF8(string(data), "") == string(data)
+	return charset.Name, nil
 }
\ No newline at end of file
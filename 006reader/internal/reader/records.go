@@ -0,0 +1,44 @@
+package reader
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Records holds lines parsed into columns by a structured format such as
+// CSV or JSON Lines.
+type Records struct {
+	Format		string		`json:"format"`
+	Columns		[]string	`json:"columns"`
+	Rows		[]Record	`json:"rows"`
+	// Unparsed counts lines that did not fit the format and were dropped
+	Unparsed	int			`json:"unparsed,omitempty"`
+}
+
+type Record struct {
+	Line	int				`json:"line,omitempty"`
+	Offset	int64			`json:"offset"`
+	Fields	map[string]any	`json:"fields"`
+}
+
+// Text renders a field for display. Strings are shown as they are and
+// anything else as JSON; a missing field is empty.
+func (r Record) Text(column string) string {
+	switch v := r.Fields[column].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	encoded, err := json.Marshal(r.Fields[column])
+	if err != nil {
+		return fmt.Sprint(r.Fields[column])
+	}
+	return string(encoded)
+}
\ No newline at end of file
@@ -0,0 +1,162 @@
+package reader
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type SearchConfig struct {
+	// Patterns are alternatives; a line matching any of them is selected
+	Patterns	[]string
+	Literal		bool
+	IgnoreCase	bool
+	// Invert selects the lines that do not match
+	Invert		bool
+	// Before and After are the lines of context kept around each match
+	Before		int
+	After		int
+	// MaxCount stops reading after this many selected lines, 0 for no limit
+	MaxCount	int
+}
+
+// Span is the byte range of a match within a line's UTF-8 content.
+type Span struct {
+	Start	int	`json:"start"`
+	End		int	`json:"end"`
+}
+
+// SearchResult summarises a search in the metadata.
+type SearchResult struct {
+	Patterns	[]string	`json:"patterns"`
+	Invert		bool		`json:"invert,omitempty"`
+	Before		int			`json:"before,omitempty"`
+	After		int			`json:"after,omitempty"`
+	// MatchCount is the number of selected lines, not counting context
+	MatchCount	int			`json:"match_count"`
+}
+
+type Matcher struct {
+	re		*regexp.Regexp
+	invert	bool
+}
+
+func NewMatcher(config *SearchConfig) (*Matcher, error) {
+	if len(config.Patterns) == 0 {
+		return nil, fmt.Errorf("no search pattern given")
+	}
+
+	alternatives := make([]string, len(config.Patterns))
+	for i, pattern := range config.Patterns {
+		if config.Literal {
+			pattern = regexp.QuoteMeta(pattern)
+		}
+		alternatives[i] = "(?:" + pattern + ")"
+	}
+
+	expr := strings.Join(alternatives, "|")
+	if config.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	return &Matcher{re: re, invert: config.Invert}, nil
+}
+
+// Match reports whether the line is selected and where the patterns matched
+// in it. Inverted matchers select lines without matches, so they never
+// return spans.
+func (m *Matcher) Match(text string) ([]Span, bool) {
+	indexes := m.re.FindAllStringIndex(text, -1)
+	if m.invert {
+		return nil, indexes == nil
+	}
+	if indexes == nil {
+		return nil, false
+	}
+
+	var spans []Span
+	for _, index := range indexes {
+		// empty matches select the line but have nothing to highlight
+		if index[1] > index[0] {
+			spans = append(spans, Span{Start: index[0], End: index[1]})
+		}
+	}
+	return spans, true
+}
+
+// searchState filters a stream of lines down to the matches and their
+// context, holding at most Before lines back.
+type searchState struct {
+	config		*SearchConfig
+	matcher		*Matcher
+	before		[]Line
+	afterLeft	int
+	matched		int
+}
+
+func newSearchState(config *SearchConfig) (*searchState, error) {
+	matcher, err := NewMatcher(config)
+	if err != nil {
+		return nil, err
+	}
+	return &searchState{config: config, matcher: matcher}, nil
+}
+
+// feed takes the next line and returns the lines to output: the context held
+// back before a match and the match itself, or a line of trailing context.
+func (s *searchState) feed(line Line) []Line {
+	if s.limitReached() {
+		if s.afterLeft > 0 {
+			s.afterLeft--
+			line.Context = true
+			return []Line{line}
+		}
+		return nil
+	}
+
+	if spans, ok := s.matcher.Match(line.Content); ok {
+		line.Matches = spans
+		out := append(s.before, line)
+		s.before = nil
+		s.afterLeft = s.config.After
+		s.matched++
+		return out
+	}
+
+	line.Context = true
+	if s.afterLeft > 0 {
+		s.afterLeft--
+		return []Line{line}
+	}
+	if s.config.Before > 0 {
+		s.before = append(s.before, line)
+		if len(s.before) > s.config.Before {
+			s.before = s.before[1:]
+		}
+	}
+	return nil
+}
+
+func (s *searchState) limitReached() bool {
+	return s.config.MaxCount > 0 && s.matched >= s.config.MaxCount
+}
+
+// finished reports whether no later line can be output.
+func (s *searchState) finished() bool {
+	return s.limitReached() && s.afterLeft == 0
+}
+
+func (s *searchState) result() *SearchResult {
+	return &SearchResult{
+		Patterns: s.config.Patterns,
+		Invert: s.config.Invert,
+		Before: s.config.Before,
+		After: s.config.After,
+		MatchCount: s.matched,
+	}
+}
\ No newline at end of file
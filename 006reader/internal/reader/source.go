@@ -0,0 +1,423 @@
+package reader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+const (
+	CompressionGzip		= "gzip"
+	CompressionBzip2	= "bzip2"
+	CompressionZstd		= "zstd"
+	CompressionXz		= "xz"
+
+	ArchiveZip	= "zip"
+	ArchiveTar	= "tar"
+)
+
+// magic numbers identify compression regardless of the file name
+var magics = []struct {
+	compression	string
+	magic		[]byte
+}{
+	{CompressionGzip, []byte{0x1f, 0x8b}},
+	{CompressionBzip2, []byte("BZh")},
+	{CompressionZstd, []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{CompressionXz, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+}
+
+var zipMagic = []byte("PK\x03\x04")
+
+// Member is an entry of a zip or tar archive.
+type Member struct {
+	Name			string		`json:"name"`
+	Size			int64		`json:"size"`
+	CompressedSize	int64		`json:"compressed_size,omitempty"`
+	ModTime			time.Time	`json:"mod_time"`
+	Dir				bool		`json:"dir,omitempty"`
+}
+
+// source is the data behind a path. Plain files are read directly so they
+// can be seeked; compressed files and archive members are streams.
+type source struct {
+	file				*os.File
+	stream				io.Reader
+	closers				[]io.Closer
+	name				string
+	size				int64
+	modTime				time.Time
+	compression			string
+	archive				string
+	member				string
+	compressedSize		int64
+	uncompressedSize	int64
+}
+
+// SplitArchivePath splits "logs.tar.gz:app/server.log" into the archive and
+// the member inside it. Paths that exist as they are are never split.
+func SplitArchivePath(filePath string) (string, string) {
+	if _, err := os.Stat(filePath); err == nil {
+		return filePath, ""
+	}
+
+	for i := 0; i < len(filePath); i++ {
+		if filePath[i] != ':' {
+			continue
+		}
+		if info, err := os.Stat(filePath[:i]); err == nil && !info.IsDir() {
+			return filePath[:i], filePath[i+1:]
+		}
+	}
+	return filePath, ""
+}
+
+// DetectCompression reports how the file at filePath is compressed or
+// archived, going by its first bytes: one of the Compression constants,
+// ArchiveZip, or "" for anything else.
+func DetectCompression(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	head := make([]byte, 8)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	return detectMagic(head[:n]), nil
+}
+
+func detectMagic(head []byte) string {
+	if bytes.HasPrefix(head, zipMagic) {
+		return ArchiveZip
+	}
+	for _, m := range magics {
+		if bytes.HasPrefix(head, m.magic) {
+			return m.compression
+		}
+	}
+	return ""
+}
+
+// ErrTooLarge is returned when decompressed data grows past the size limit.
+var ErrTooLarge = errors.New("file too large")
+
+// openSource opens the data behind filePath. Streams of decompressed data
+// fail with ErrTooLarge after maxSize bytes, when maxSize is positive.
+func openSource(filePath string, maxSize int64) (*source, error) {
+	archivePath, member := SplitArchivePath(filePath)
+
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	src := &source{
+		closers: []io.Closer{file},
+		name: filepath.Base(archivePath),
+		size: info.Size(),
+		modTime: info.ModTime(),
+		compressedSize: info.Size(),
+		uncompressedSize: -1,
+	}
+
+	head := make([]byte, 8)
+	n, _ := file.ReadAt(head, 0)
+	kind := detectMagic(head[:n])
+
+	switch {
+	case kind == ArchiveZip:
+		err = src.openZip(archivePath, member)
+	case isTar(archivePath):
+		err = src.openTar(file, kind, member)
+	case member != "":
+		err = fmt.Errorf("%s is not an archive", archivePath)
+	case kind != "":
+		src.compression = kind
+		src.stream, err = src.decompress(file, kind)
+	default:
+		src.file = file
+		src.compressedSize = 0
+		src.uncompressedSize = info.Size()
+	}
+	if err != nil {
+		src.Close()
+		return nil, err
+	}
+
+	if src.stream != nil && maxSize > 0 {
+		src.stream = &limitReader{r: src.stream, remaining: maxSize, max: maxSize}
+	}
+
+	return src, nil
+}
+
+// limitReader fails a stream that yields more than max bytes, so a small
+// compressed file cannot expand without bound.
+type limitReader struct {
+	r			io.Reader
+	remaining	int64
+	max			int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, fmt.Errorf("%w: over %d bytes once decompressed", ErrTooLarge, l.max)
+	}
+
+	// read one byte past the limit to tell a stream that ends exactly at
+	// it from one that goes on
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), fmt.Errorf("%w: over %d bytes once decompressed", ErrTooLarge, l.max)
+	}
+	return n, err
+}
+
+func (s *source) openZip(archivePath, member string) error {
+	s.archive = ArchiveZip
+	if member == "" {
+		return fmt.Errorf("%s is a zip archive: name a member as %s:<path> or list them", archivePath, archivePath)
+	}
+
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open zip archive: %w", err)
+	}
+	s.closers = append(s.closers, archive)
+
+	for _, entry := range archive.File {
+		if cleanMember(entry.Name) != cleanMember(member) || entry.FileInfo().IsDir() {
+			continue
+		}
+
+		stream, err := entry.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", member, err)
+		}
+		s.closers = append(s.closers, stream)
+
+		s.member = entry.Name
+		s.name = path.Base(entry.Name)
+		s.modTime = entry.Modified
+		s.compressedSize = int64(entry.CompressedSize64)
+		s.uncompressedSize = int64(entry.UncompressedSize64)
+		return s.unwrap(stream)
+	}
+
+	return fmt.Errorf("no member %s in %s", member, archivePath)
+}
+
+func (s *source) openTar(file *os.File, compression, member string) error {
+	s.archive = ArchiveTar
+	s.compression = compression
+	if member == "" {
+		return fmt.Errorf("%s is a tar archive: name a member as %s:<path> or list them", file.Name(), file.Name())
+	}
+
+	stream, err := s.decompress(file, compression)
+	if err != nil {
+		return err
+	}
+
+	archive := tar.NewReader(stream)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return fmt.Errorf("no member %s in %s", member, file.Name())
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || cleanMember(header.Name) != cleanMember(member) {
+			continue
+		}
+
+		s.member = header.Name
+		s.name = path.Base(header.Name)
+		s.modTime = header.ModTime
+		// members of a compressed tar have no size of their own
+		s.compressedSize = 0
+		s.uncompressedSize = header.Size
+		return s.unwrap(archive)
+	}
+}
+
+// unwrap decompresses an archive member that is itself compressed.
+func (s *source) unwrap(stream io.Reader) error {
+	buffered := bufio.NewReader(stream)
+	head, _ := buffered.Peek(8)
+
+	kind := detectMagic(head)
+	if kind == "" || kind == ArchiveZip {
+		s.stream = buffered
+		return nil
+	}
+
+	var err error
+	s.compression = kind
+	s.uncompressedSize = -1
+	s.stream, err = s.decompress(buffered, kind)
+	return err
+}
+
+func (s *source) decompress(r io.Reader, compression string) (io.Reader, error) {
+	switch compression {
+	case "":
+		return r, nil
+	case CompressionGzip:
+		stream, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		s.closers = append(s.closers, stream)
+		return stream, nil
+	case CompressionBzip2:
+		return bzip2.NewReader(r), nil
+	case CompressionZstd:
+		stream, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open zstd stream: %w", err)
+		}
+		s.closers = append(s.closers, stream.IOReadCloser())
+		return stream, nil
+	case CompressionXz:
+		stream, err := xz.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open xz stream: %w", err)
+		}
+		return stream, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression: %s", compression)
+	}
+}
+
+func (s *source) Close() error {
+	var first error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		if err := s.closers[i].Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// describe copies what is known about the source into the metadata.
+func (s *source) describe(meta *Metadata) {
+	meta.FileName = s.name
+	meta.Size = s.size
+	meta.ModTime = s.modTime
+	meta.Compression = s.compression
+	meta.Archive = s.archive
+	meta.Member = s.member
+	meta.CompressedSize = s.compressedSize
+	if s.uncompressedSize >= 0 && s.file == nil {
+		meta.UncompressedSize = s.uncompressedSize
+	}
+}
+
+// ListArchive returns the members of a zip or tar archive, which may itself
+// be compressed.
+func ListArchive(archivePath string) ([]Member, error) {
+	kind, err := DetectCompression(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	if kind == ArchiveZip {
+		archive, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open zip archive: %w", err)
+		}
+		defer archive.Close()
+
+		var members []Member
+		for _, entry := range archive.File {
+			members = append(members, Member{
+				Name: entry.Name,
+				Size: int64(entry.UncompressedSize64),
+				CompressedSize: int64(entry.CompressedSize64),
+				ModTime: entry.Modified,
+				Dir: entry.FileInfo().IsDir(),
+			})
+		}
+		return members, nil
+	}
+
+	if !isTar(archivePath) {
+		return nil, fmt.Errorf("%s is not a zip or tar archive", archivePath)
+	}
+
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	src := &source{closers: []io.Closer{file}}
+	defer src.Close()
+
+	stream, err := src.decompress(file, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []Member
+	archive := tar.NewReader(stream)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return members, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar archive: %w", err)
+		}
+		members = append(members, Member{
+			Name: header.Name,
+			Size: header.Size,
+			ModTime: header.ModTime,
+			Dir: header.Typeflag == tar.TypeDir,
+		})
+	}
+}
+
+// isTar goes by the name, since a compressed tar can only be recognised
+// after decompressing it.
+func isTar(filePath string) bool {
+	name := strings.ToLower(filePath)
+	for _, ext := range []string{".tar", ".tgz", ".tbz2", ".txz", ".tzst", ".tar.gz", ".tar.bz2", ".tar.xz", ".tar.zst"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func cleanMember(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
\ No newline at end of file
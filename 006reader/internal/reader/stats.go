@@ -0,0 +1,131 @@
+package reader
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Stats summarises the lines of a file.
+type Stats struct {
+	Metadata			Metadata		`json:"metadata"`
+	Lines				int				`json:"lines"`
+	Words				int				`json:"words"`
+	Chars				int				`json:"chars"`
+	EmptyLines			int				`json:"empty_lines"`
+	LongestLine			int				`json:"longest_line"`
+	LongestLineNumber	int				`json:"longest_line_number,omitempty"`
+	// LineEnding is EndingLF, EndingCRLF, "mixed", or "none" for a file
+	// without line breaks
+	LineEnding			string			`json:"line_ending"`
+	LFLines				int				`json:"lf_lines"`
+	CRLFLines			int				`json:"crlf_lines"`
+	FinalNewline		bool			`json:"final_newline"`
+	TrailingWhitespace	int				`json:"trailing_whitespace"`
+	// TopLines are the most repeated non-empty lines, most frequent first
+	TopLines			[]RepeatedLine	`json:"top_lines,omitempty"`
+}
+
+type RepeatedLine struct {
+	Content	string	`json:"content"`
+	Count	int		`json:"count"`
+}
+
+// Stats reads the lines selected by the config and counts them. Only lines
+// seen more than once are kept as top lines, at most top of them.
+func (r *Reader) Stats(top int) (*Stats, error) {
+	startTime := time.Now()
+
+	src, err := openSource(r.config.FilePath, r.config.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	stats := &Stats{Metadata: Metadata{FilePath: r.config.FilePath}}
+	src.describe(&stats.Metadata)
+
+	counts := make(map[string]int)
+	var last Line
+	err = r.lines(src, &stats.Metadata, true, func(line Line) error {
+		stats.add(line)
+		if strings.TrimSpace(line.Content) != "" {
+			counts[line.Content]++
+		}
+		last = line
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lines: %w", err)
+	}
+
+	stats.FinalNewline = stats.Lines > 0 && last.Ending != ""
+	switch {
+	case stats.LFLines > 0 && stats.CRLFLines > 0:
+		stats.LineEnding = "mixed"
+	case stats.CRLFLines > 0:
+		stats.LineEnding = EndingCRLF
+	case stats.LFLines > 0:
+		stats.LineEnding = EndingLF
+	default:
+		stats.LineEnding = "none"
+	}
+
+	stats.TopLines = topLines(counts, top)
+	stats.Metadata.LineCount = stats.Lines
+	stats.Metadata.ReadTime = time.Since(startTime)
+
+	return stats, nil
+}
+
+func (s *Stats) add(line Line) {
+	s.Lines++
+	s.Words += len(strings.Fields(line.Content))
+
+	chars := utf8.RuneCountInString(line.Content)
+	s.Chars += chars
+	if s.Lines == 1 || chars > s.LongestLine {
+		s.LongestLine = chars
+		s.LongestLineNumber = line.Number
+	}
+
+	if line.Content == "" {
+		s.EmptyLines++
+	} else if last, _ := utf8.DecodeLastRuneInString(line.Content); unicode.IsSpace(last) {
+		s.TrailingWhitespace++
+	}
+
+	// like wc, line endings count as characters
+	switch line.Ending {
+	case EndingLF:
+		s.LFLines++
+		s.Chars++
+	case EndingCRLF:
+		s.CRLFLines++
+		s.Chars += 2
+	}
+}
+
+func topLines(counts map[string]int, top int) []RepeatedLine {
+	var repeated []RepeatedLine
+	for content, count := range counts {
+		if count > 1 {
+			repeated = append(repeated, RepeatedLine{Content: content, Count: count})
+		}
+	}
+
+	sort.Slice(repeated, func(i, j int) bool {
+		if repeated[i].Count != repeated[j].Count {
+			return repeated[i].Count > repeated[j].Count
+		}
+		return repeated[i].Content < repeated[j].Content
+	})
+
+	if top >= 0 && len(repeated) > top {
+		repeated = repeated[:top]
+	}
+	return repeated
+}
\ No newline at end of file
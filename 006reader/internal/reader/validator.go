@@ -2,6 +2,7 @@ package reader
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -21,12 +22,13 @@ type FileValidator struct {
 	allowedExts	 []string
 	blockedExts	 []string
 	checkContent bool
+	allowBinary	 bool
 }
 
 func NewValidator() *FileValidator {
 	return &FileValidator{
 		maxSize: 100 * 1024 * 1024,	// 100mb
-		allowedExts: []string{".txt", ".log", ".md", ".csv", ".json", ".xml", ".yml"},
+		allowedExts: []string{".txt", ".log", ".md", ".csv", ".json", ".xml", ".yml", ".yaml", ".go", ".sh", ".sql", ".zip", ".tar", ".tgz"},
 		blockedExts: []string{".exe", ".bin", ".so", ".dll"},
 		checkContent: true,	
 	}
@@ -52,8 +54,18 @@ func (v *FileValidator) EnableContentCheck(enable bool) *FileValidator {
 	return v
 }
 
+// AllowBinary lets binary content through the content check, for views
+// such as hex dumps that do not treat the file as text.
+func (v *FileValidator) AllowBinary(allow bool) *FileValidator {
+	v.allowBinary = allow
+	return v
+}
+
 func (v *FileValidator) Validate(filePath string) error {
-	info, err := os.Stat(filePath)
+	// a member inside an archive is checked against the archive on disk
+	archivePath, _ := SplitArchivePath(filePath)
+
+	info, err := os.Stat(archivePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return &ValidationError{filePath, "file does not exist"}
@@ -73,7 +85,7 @@ func (v *FileValidator) Validate(filePath string) error {
 		return err
 	}
 
-	if err := v.validatePermissions(filePath); err != nil {
+	if err := v.validatePermissions(archivePath); err != nil {
 		return err
 	}
 
@@ -87,7 +99,7 @@ func (v *FileValidator) Validate(filePath string) error {
 }
 
 func (v *FileValidator) validateExtension(filePath string) error {
-	ext := strings.ToLower(filepath.Ext(filePath))
+	ext := contentExt(filePath)
 
 	for _, blocked := range v.blockedExts {
 		if ext == strings.ToLower(blocked) {
@@ -122,18 +134,37 @@ func (v *FileValidator) validatePermissions(filePath string) error {
 }
 
 func (v *FileValidator) validateContent(filePath string) error {
-	file, err := os.Open(filePath)
+	// archives are only listed as they are; their members are checked when named
+	if archivePath, member := SplitArchivePath(filePath); member == "" {
+		kind, err := DetectCompression(archivePath)
+		if err == nil && (kind == ArchiveZip || isTar(archivePath)) {
+			return nil
+		}
+	}
+
+	// compressed files are checked on what they decompress to
+	src, err := openSource(filePath, 0)
 	if err != nil {
 		return &ValidationError{filePath, fmt.Sprintf("cannot open for content validation: %v", err)}
 	}
-	defer file.Close()
+	defer src.Close()
+
+	stream := src.stream
+	if src.file != nil {
+		stream = src.file
+	}
 
 	buffer := make([]byte, 1024)
-	n, err := file.Read(buffer)
-	if err != nil && err.Error() != "EOF" {
+	n, err := io.ReadFull(stream, buffer)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
 		return &ValidationError{filePath, fmt.Sprintf("cannot read file content: %v", err)}
 	}
 
+	// zero bytes are expected in UTF-16 text
+	if v.allowBinary || DetectCharset(buffer[:n]).width == 2 {
+		return nil
+	}
+
 	for i := 0; i < n;  i++ {
 		if buffer[i] == 0 {
 			return &ValidationError{filePath, "file appears to be binary"}
@@ -153,8 +184,23 @@ func (v *FileValidator) ValidateMultiple(filePaths []string) map[string]error {
 	return results
 }
 
-func GetFileType(filePath string) string {
+// contentExt is the extension of what a file holds, so "app.log.gz" and
+// "logs.tar:app.log" are both ".log".
+func contentExt(filePath string) string {
+	if _, member := SplitArchivePath(filePath); member != "" {
+		filePath = member
+	}
+
 	ext := strings.ToLower(filepath.Ext(filePath))
+	switch ext {
+	case ".gz", ".bz2", ".zst", ".xz":
+		return strings.ToLower(filepath.Ext(strings.TrimSuffix(filePath, filepath.Ext(filePath))))
+	}
+	return ext
+}
+
+func GetFileType(filePath string) string {
+	ext := contentExt(filePath)
 
 	switch ext {
 	case ".txt":
@@ -171,6 +217,14 @@ func GetFileType(filePath string) string {
 		return "csv"
 	case ".yml", ".yaml":
 		return "yaml"
+	case ".go":
+		return "go"
+	case ".sh", ".bash", ".zsh":
+		return "shell"
+	case ".sql":
+		return "sql"
+	case ".zip", ".tar", ".tgz":
+		return "archive"
 	default:
 		return "unknown"
 	}
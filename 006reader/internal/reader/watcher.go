@@ -1,15 +1,19 @@
 package reader
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
-	"strings"
+	"path/filepath"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 )
 
 type WatchEvent struct {
+	// File is the path the event came from
+	File    string    `json:"file,omitempty"`
 	Type    string    `json:"type"`
 	Content string    `json:"content"`
 	Time    time.Time `json:"time"`
@@ -21,16 +25,34 @@ type WatchConfig struct {
 	TailMode     bool
 	PollInterval int
 	BufferSize   int
+	// Encoding names the file's charset; "auto" or "" detects it
+	Encoding string
+	// Poll checks the file every PollInterval instead of using fsnotify
+	Poll bool
+	// FinishRotated reads what was still written to a rotated file before
+	// moving on to its replacement
+	FinishRotated bool
 }
 
+// Watcher follows a file like tail -F. It remembers the byte offset it has
+// read up to and only reads what was appended since, so the file is never
+// read twice. Truncation starts it over from the top, and when the path is
+// rotated to a new file it re-opens it.
 type Watcher struct {
-	config      *WatchConfig
-	watcher     *fsnotify.Watcher
-	events      chan WatchEvent
-	errors      chan error
-	lastSize    int64
-	lastLineNum int
-	done        chan bool
+	config  *WatchConfig
+	watcher *fsnotify.Watcher
+	events  chan WatchEvent
+	errors  chan error
+	done    chan bool
+	polling bool
+
+	// the rest is only touched by the run goroutine
+	file     *os.File
+	fileInfo os.FileInfo
+	charset  *Charset
+	offset   int64
+	lineNum  int
+	missing  bool
 }
 
 func NewWatcher(config *WatchConfig) *Watcher {
@@ -42,71 +64,70 @@ func NewWatcher(config *WatchConfig) *Watcher {
 	}
 }
 
-func (w *Watcher) Start() error {
-	var err error
-	w.watcher, err = fsnotify.NewWatcher()
-	if err != nil {
-		return fmt.Errorf("failed to create watcher: %w", err)
+// newFollower creates a Watcher that shares its channels with a MultiWatcher,
+// which drives it from its own goroutine instead of calling Start.
+func newFollower(config *WatchConfig, events chan WatchEvent, errors chan error, done chan bool) *Watcher {
+	return &Watcher{
+		config: config,
+		events: events,
+		errors: errors,
+		done:   done,
 	}
+}
 
-	if err := w.watcher.Add(w.config.FilePath); err != nil {
-		return fmt.Errorf("failed to watch file: %w", err)
+func (w *Watcher) Start() error {
+	if err := w.open(); err != nil {
+		return err
 	}
 
-	if info, err := os.Stat(w.config.FilePath); err == nil {
-		w.lastSize = info.Size()
-		if w.config.TailMode {
-			// in tail mode, lets count existing lines and start from the bottom
-			w.lastLineNum = w.countLines()
+	if w.config.TailMode {
+		// start from the bottom, counting what is skipped to keep line numbers
+		info, err := w.file.Stat()
+		if err != nil {
+			w.file.Close()
+			return fmt.Errorf("failed to stat file: %w", err)
+		}
+		if err := w.skipTo(info.Size()); err != nil {
+			w.file.Close()
+			return err
 		}
 	}
 
-	go w.processEvents()
-
-	if !w.config.TailMode {
-		w.sendInitialContent()
+	if !w.config.Poll {
+		if err := w.startNotify(); err != nil {
+			// fsnotify is missing on some platforms and file systems
+			w.polling = true
+		}
+	} else {
+		w.polling = true
 	}
 
+	go w.run()
+
 	return nil
 }
 
-func (w *Watcher) sendInitialContent() {
-	reader := New(&Config{
-		FilePath:   w.config.FilePath,
-		BufferSize: w.config.BufferSize,
-	})
-
-	content, err := reader.Read()
+// startNotify watches the file's directory rather than the file itself, so
+// events keep coming after the file is renamed or replaced.
+func (w *Watcher) startNotify() error {
+	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
-		w.errors <- fmt.Errorf("failed to read initial content: %w", err)
-		return
+		return fmt.Errorf("failed to create watcher: %w", err)
 	}
 
-	// Send each line as an event
-	for _, line := range content.Lines {
-		w.events <- WatchEvent{
-			Type:    "initial",
-			Content: line.Content,
-			Time:    time.Now(),
-			LineNum: line.Number,
-		}
+	if err := watcher.Add(filepath.Dir(w.config.FilePath)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch file: %w", err)
 	}
 
-	w.lastLineNum = len(content.Lines)
+	w.watcher = watcher
+	return nil
 }
 
-func (w *Watcher) countLines() int {
-	reader := New(&Config{
-		FilePath:   w.config.FilePath,
-		BufferSize: w.config.BufferSize,
-	})
-
-	content, err := reader.Read()
-	if err != nil {
-		return 0
-	}
-
-	return len(content.Lines)
+// Polling reports whether the file is checked on a timer because fsnotify
+// was unavailable or disabled.
+func (w *Watcher) Polling() bool {
+	return w.polling
 }
 
 func (w *Watcher) Stop() {
@@ -124,20 +145,38 @@ func (w *Watcher) Errors() <-chan error {
 	return w.errors
 }
 
-func (w *Watcher) processEvents() {
+func (w *Watcher) run() {
+	defer func() {
+		if w.file != nil {
+			w.file.Close()
+		}
+	}()
+
+	if !w.config.TailMode {
+		w.readAppended("initial")
+	}
+
+	if w.polling {
+		w.poll()
+		return
+	}
+
+	target := filepath.Clean(w.config.FilePath)
 	for {
 		select {
 		case event, ok := <-w.watcher.Events:
 			if !ok {
 				return
 			}
-			w.handleFileEvent(event)
+			if filepath.Clean(event.Name) == target {
+				w.check()
+			}
 
 		case err, ok := <-w.watcher.Errors:
 			if !ok {
 				return
 			}
-			w.errors <- err
+			w.sendError(err)
 
 		case <-w.done:
 			return
@@ -145,88 +184,212 @@ func (w *Watcher) processEvents() {
 	}
 }
 
-func (w *Watcher) handleFileEvent(event fsnotify.Event) {
-	if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) {
-		w.handleFileWrite()
-	} else if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
-		w.events <- WatchEvent{
-			Type:    "removed",
-			Content: "File was removed or renamed",
-			Time:    time.Now(),
+func (w *Watcher) poll() {
+	interval := time.Duration(w.config.PollInterval) * time.Millisecond
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.check()
+		case <-w.done:
+			return
 		}
 	}
 }
 
-func (w *Watcher) handleFileWrite() {
+// check compares the path with the open file and reads whatever is new.
+func (w *Watcher) check() {
 	info, err := os.Stat(w.config.FilePath)
+	if os.IsNotExist(err) {
+		// renamed away or deleted; keep what was written before it went and
+		// wait for the path to come back
+		w.readAppended("new_line")
+		if !w.missing {
+			w.missing = true
+			w.send(WatchEvent{
+				Type:    "removed",
+				Content: "File was removed or renamed, waiting for it to reappear",
+				Time:    time.Now(),
+			})
+		}
+		return
+	}
 	if err != nil {
-		w.errors <- fmt.Errorf("failed to stat file: %w", err)
+		w.sendError(fmt.Errorf("failed to stat file: %w", err))
 		return
 	}
 
-	currentSize := info.Size()
-	if currentSize == w.lastSize {
+	if !os.SameFile(info, w.fileInfo) {
+		w.rotate()
 		return
 	}
+	w.missing = false
 
-	//read new content
-	if w.config.TailMode {
-		w.readNewLines()
-	} else {
-		w.readFullFile()
+	if info.Size() < w.offset {
+		w.send(WatchEvent{
+			Type:    "truncated",
+			Content: "File was truncated, reading from the start",
+			Time:    time.Now(),
+		})
+		w.offset = int64(w.charset.BOMLength)
+		w.lineNum = 0
 	}
 
-	w.lastSize = currentSize
+	w.readAppended("new_line")
 }
 
-func (w *Watcher) readFullFile() {
-	reader := New(&Config{
-		FilePath:   w.config.FilePath,
-		BufferSize: w.config.BufferSize,
-	})
+// rotate switches to the new file now found at the path.
+func (w *Watcher) rotate() {
+	if w.config.FinishRotated {
+		w.readAppended("new_line")
+	}
 
-	content, err := reader.Read()
-	if err != nil {
-		w.errors <- fmt.Errorf("failed to read file: %w", err)
+	old := w.file
+	if err := w.open(); err != nil {
+		w.sendError(err)
 		return
 	}
+	old.Close()
 
-	
-	w.lastLineNum = len(content.Lines)
+	w.missing = false
+	w.send(WatchEvent{
+		Type:    "rotated",
+		Content: "File was rotated, following the new file",
+		Time:    time.Now(),
+	})
+	w.readAppended("new_line")
+}
 
-	
-	var fullContent strings.Builder
-	for _, line := range content.Lines {
-		fullContent.WriteString(line.Content)
-		fullContent.WriteString("\n")
+// open opens the file at the path and positions the watcher at its start.
+func (w *Watcher) open() error {
+	file, err := os.Open(w.config.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
 	}
 
-	w.events <- WatchEvent{
-		Type:    "full_update",
-		Content: fullContent.String(),
-		Time:    time.Now(),
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat file: %w", err)
 	}
+
+	charset, err := ResolveCharset(file, w.config.Encoding)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	w.file = file
+	w.fileInfo = info
+	w.charset = charset
+	w.offset = int64(charset.BOMLength)
+	w.lineNum = 0
+	return nil
 }
 
-func (w *Watcher) readNewLines() {
-	reader := New(&Config{
-		FilePath:   w.config.FilePath,
-		BufferSize: w.config.BufferSize,
+// skipTo moves the offset to the end of the last complete line before size,
+// counting the lines passed over.
+func (w *Watcher) skipTo(size int64) error {
+	return w.scan(size, func(string) bool {
+		return true
 	})
+}
 
-	content, err := reader.Read()
+// readAppended sends every complete line written since the last read.
+func (w *Watcher) readAppended(eventType string) {
+	info, err := w.file.Stat()
 	if err != nil {
-		w.errors <- fmt.Errorf("failed to read file: %w", err)
+		w.sendError(fmt.Errorf("failed to stat file: %w", err))
 		return
 	}
 
-	for i := w.lastLineNum; i < len(content.Lines); i++ {
-		line := content.Lines[i]
-		w.events <- WatchEvent{
-			Type:    "new_line",
-			Content: line.Content,
+	err = w.scan(info.Size(), func(line string) bool {
+		return w.send(WatchEvent{
+			Type:    eventType,
+			Content: line,
 			Time:    time.Now(),
-			LineNum: line.Number,
+			LineNum: w.lineNum,
+		})
+	})
+	if err != nil {
+		w.sendError(err)
+	}
+}
+
+// scan reads the lines between the offset and size, advancing past each one
+// handed to fn. A last line without its newline is still being written, so
+// it is left for the next scan.
+func (w *Watcher) scan(size int64, fn func(string) bool) error {
+	if size <= w.offset {
+		return nil
+	}
+
+	if _, err := w.file.Seek(w.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek file: %w", err)
+	}
+
+	complete, err := w.endsWithNewline(size)
+	if err != nil {
+		return err
+	}
+
+	scanner := NewLineScanner(io.LimitReader(w.file, size-w.offset), w.offset, w.config.BufferSize, w.charset)
+	for {
+		line, _, err := scanner.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+		if scanner.Offset() == size && !complete {
+			return nil
 		}
+
+		w.offset = scanner.Offset()
+		w.lineNum++
+		if !fn(line) {
+			return nil
+		}
+	}
+}
+
+func (w *Watcher) endsWithNewline(size int64) (bool, error) {
+	newline := w.charset.unit('\n')
+	if size < int64(len(newline)) {
+		return false, nil
+	}
+
+	last := make([]byte, len(newline))
+	if _, err := w.file.ReadAt(last, size-int64(len(newline))); err != nil {
+		return false, fmt.Errorf("failed to read file: %w", err)
+	}
+	return bytes.Equal(last, newline), nil
+}
+
+// send delivers an event unless the watcher is stopped first.
+func (w *Watcher) send(event WatchEvent) bool {
+	event.File = w.config.FilePath
+
+	select {
+	case w.events <- event:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+func (w *Watcher) sendError(err error) {
+	err = fmt.Errorf("%s: %w", w.config.FilePath, err)
+
+	select {
+	case w.errors <- err:
+	case <-w.done:
 	}
 }
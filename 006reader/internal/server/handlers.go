@@ -0,0 +1,245 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/samnart1/GoLang/006reader/internal/formatter"
+	"github.com/samnart1/GoLang/006reader/internal/logger"
+	"github.com/samnart1/GoLang/006reader/internal/reader"
+)
+
+type entry struct {
+	Name	string		`json:"name"`
+	Path	string		`json:"path"`
+	Dir		bool		`json:"dir"`
+	Size	int64		`json:"size"`
+	ModTime	time.Time	`json:"mod_time"`
+	// Binary files can only be read as hex dumps
+	Binary	bool		`json:"binary,omitempty"`
+}
+
+type listing struct {
+	Path	string	`json:"path"`
+	Entries	[]entry	`json:"entries"`
+}
+
+// handleList lists a directory. Files the validator would refuse to serve
+// are left out, and binary ones are marked.
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	dir, err := s.resolve(r.URL.Query().Get("path"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	items, err := os.ReadDir(dir)
+	if err != nil {
+		s.writeError(w, &httpError{http.StatusBadRequest, fmt.Sprintf("cannot list %s: not a directory", s.relative(dir))})
+		return
+	}
+
+	result := listing{Path: s.relative(dir), Entries: []entry{}}
+	for _, item := range items {
+		full := filepath.Join(dir, item.Name())
+		info, err := os.Stat(full)
+		if err != nil {
+			continue
+		}
+		// symlinks are listed only when they stay inside the root
+		if _, err := s.resolve(s.relative(full)); err != nil {
+			continue
+		}
+		if !info.IsDir() && s.binary.Validate(full) != nil {
+			continue
+		}
+
+		result.Entries = append(result.Entries, entry{
+			Name: item.Name(),
+			Path: s.relative(full),
+			Dir: info.IsDir(),
+			Size: info.Size(),
+			ModTime: info.ModTime(),
+			Binary: !info.IsDir() && s.validator.Validate(full) != nil,
+		})
+	}
+
+	sort.SliceStable(result.Entries, func(i, j int) bool {
+		return result.Entries[i].Dir && !result.Entries[j].Dir
+	})
+
+	s.writeJSON(w, http.StatusOK, result)
+}
+
+// handleRead reads a file with the same options as the read command and
+// returns it in the requested format. Only hex dumps may show binary files.
+func (s *Server) handleRead(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = "plain"
+	}
+
+	full, err := s.resolveFile(query.Get("path"), format == "hex")
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	// the validator checks the size on disk; this bounds what compressed
+	// files and archive members expand to
+	readerConfig := reader.Config{
+		FilePath: full,
+		Encoding: s.config.Encoding,
+		BufferSize: s.config.BufferSize,
+		MaxSize: s.config.MaxFileSize,
+	}
+	if encoding := query.Get("encoding"); encoding != "" {
+		readerConfig.Encoding = encoding
+	}
+
+	ints := map[string]*int{
+		"max_lines": &readerConfig.MaxLines,
+		"from_line": &readerConfig.FromLine,
+		"to_line": &readerConfig.ToLine,
+		"tail": &readerConfig.Tail,
+	}
+	for name, target := range ints {
+		if *target, err = intParam(query, name); err != nil {
+			s.writeError(w, err)
+			return
+		}
+	}
+	if readerConfig.Offset, err = int64Param(query, "offset"); err != nil {
+		s.writeError(w, err)
+		return
+	}
+	if readerConfig.Length, err = int64Param(query, "length"); err != nil {
+		s.writeError(w, err)
+		return
+	}
+	// as in the read command, max_lines counts rows of a hex dump
+	if format == "hex" {
+		readerConfig.Raw = true
+		if rows := int64(readerConfig.MaxLines) * 16; rows > 0 && (readerConfig.Length == 0 || readerConfig.Length > rows) {
+			readerConfig.Length = rows
+		}
+	}
+	if err := readerConfig.Validate(); err != nil {
+		s.writeError(w, &httpError{http.StatusBadRequest, err.Error()})
+		return
+	}
+
+	fmtHandler, err := formatter.New(format, &formatter.Config{
+		ShowLineNumbers: query.Get("lines") == "true",
+		MaxWidth: s.config.MaxWidth,
+		Theme: s.config.Theme,
+		ColorOutput: query.Get("color") == "true",
+	})
+	if err != nil {
+		s.writeError(w, &httpError{http.StatusBadRequest, err.Error()})
+		return
+	}
+
+	content, err := reader.New(&readerConfig).Read()
+	if errors.Is(err, reader.ErrTooLarge) {
+		s.writeError(w, &httpError{http.StatusForbidden, s.redact(err)})
+		return
+	}
+	if err != nil {
+		s.log.Error("Failed to read file", logger.String("file", full), logger.Error(err))
+		s.writeError(w, fmt.Errorf("failed to read file: %w", err))
+		return
+	}
+	// clients never see where the root lives
+	content.Metadata.FilePath = s.relative(content.Metadata.FilePath)
+
+	output, err := fmtHandler.Format(content)
+	if err != nil {
+		s.writeError(w, fmt.Errorf("failed to format content: %w", err))
+		return
+	}
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	fmt.Fprintln(w, output)
+}
+
+// handleRaw serves the bytes of a file as they are on disk, honouring Range
+// requests for any part of it.
+func (s *Server) handleRaw(w http.ResponseWriter, r *http.Request) {
+	full, err := s.resolveFile(r.URL.Query().Get("path"), true)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	if _, member := reader.SplitArchivePath(full); member != "" {
+		s.writeError(w, &httpError{http.StatusBadRequest, "archive members have no raw bytes; use /api/read"})
+		return
+	}
+
+	file, err := os.Open(full)
+	if err != nil {
+		s.writeError(w, fmt.Errorf("failed to open file: %w", err))
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		s.writeError(w, fmt.Errorf("failed to get file info: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", " ")
+	if err := encoder.Encode(value); err != nil {
+		s.log.Error("Failed to write response", logger.Error(err))
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var access *httpError
+	if errors.As(err, &access) {
+		status = access.status
+	}
+	s.writeJSON(w, status, map[string]string{"error": s.redact(err)})
+}
+
+func intParam(query map[string][]string, name string) (int, error) {
+	value, err := int64Param(query, name)
+	return int(value), err
+}
+
+func int64Param(query map[string][]string, name string) (int64, error) {
+	values := query[name]
+	if len(values) == 0 || values[0] == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		return 0, &httpError{http.StatusBadRequest, fmt.Sprintf("invalid %s: %s", name, values[0])}
+	}
+	return value, nil
+}
\ No newline at end of file
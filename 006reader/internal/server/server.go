@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/samnart1/GoLang/006reader/internal/logger"
+	"github.com/samnart1/GoLang/006reader/internal/reader"
+)
+
+type Config struct {
+	// Root is the only directory served; nothing outside it can be reached
+	Root			string
+	Address			string
+	BufferSize		int
+	MaxFileSize		int64
+	Encoding		string
+	MaxWidth		int
+	Theme			string
+	// Extensions replaces the validator's allowed extensions when set
+	Extensions		[]string
+	PollInterval	int
+}
+
+// Server exposes a directory over HTTP: listings, formatted reads, raw byte
+// ranges and a live stream of watch events for a file.
+type Server struct {
+	config		Config
+	root		string
+	validator	*reader.FileValidator
+	// binary also admits binary files, for hex dumps
+	binary		*reader.FileValidator
+	log			*logger.Logger
+	httpServer	*http.Server
+	// done ends open event streams when the server shuts down
+	done		chan struct{}
+}
+
+// httpError is a failed request that is the client's doing, such as a bad
+// parameter or a path that may not be served.
+type httpError struct {
+	status	int
+	message	string
+}
+
+func (e *httpError) Error() string {
+	return e.message
+}
+
+func New(config Config, log *logger.Logger) (*Server, error) {
+	root, err := filepath.Abs(config.Root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root: %w", err)
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return nil, fmt.Errorf("invalid root: %w", err)
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("root is not a directory: %s", config.Root)
+	}
+
+	validator := reader.NewValidator().SetMaxSize(config.MaxFileSize)
+	binary := reader.NewValidator().SetMaxSize(config.MaxFileSize).AllowBinary(true)
+	if len(config.Extensions) > 0 {
+		validator.SetAllowedExtensions(config.Extensions)
+		binary.SetAllowedExtensions(config.Extensions)
+	}
+
+	s := &Server{
+		config: config,
+		root: root,
+		validator: validator,
+		binary: binary,
+		log: log,
+		done: make(chan struct{}),
+	}
+
+	// no write timeout: event streams stay open for as long as the client
+	// keeps listening
+	s.httpServer = &http.Server{
+		Addr: config.Address,
+		Handler: s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout: 120 * time.Second,
+	}
+
+	return s, nil
+}
+
+func (s *Server) Root() string {
+	return s.root
+}
+
+func (s *Server) Start() error {
+	s.log.Info("Server starting",
+		logger.String("address", s.config.Address),
+		logger.String("root", s.root))
+
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	close(s.done)
+	return s.httpServer.Shutdown(ctx)
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/files", s.handleList)
+	mux.HandleFunc("GET /api/read", s.handleRead)
+	mux.HandleFunc("GET /api/raw", s.handleRaw)
+	mux.HandleFunc("GET /api/watch", s.handleWatch)
+
+	return s.logRequests(mux)
+}
+
+func (s *Server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		s.log.Info("Request",
+			logger.String("method", r.Method),
+			logger.String("path", r.URL.Path),
+			logger.String("file", r.URL.Query().Get("path")),
+			logger.Duration("duration", time.Since(start)))
+	})
+}
+
+// resolve maps a path relative to the root onto the file system. Absolute
+// paths, ".." segments and symlinks leading out of the root are refused.
+// An archive:member path resolves the archive and keeps the member.
+func (s *Server) resolve(rel string) (string, error) {
+	rel = strings.TrimPrefix(filepath.FromSlash(rel), string(filepath.Separator))
+	if rel == "" {
+		return s.root, nil
+	}
+	if !filepath.IsLocal(rel) {
+		return "", &httpError{http.StatusForbidden, fmt.Sprintf("path outside root: %s", rel)}
+	}
+
+	full, member := reader.SplitArchivePath(filepath.Join(s.root, rel))
+
+	real, err := filepath.EvalSymlinks(full)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", &httpError{http.StatusNotFound, fmt.Sprintf("not found: %s", rel)}
+		}
+		return "", &httpError{http.StatusForbidden, fmt.Sprintf("cannot access %s", rel)}
+	}
+	if inside, err := filepath.Rel(s.root, real); err != nil || !filepath.IsLocal(inside) {
+		return "", &httpError{http.StatusForbidden, fmt.Sprintf("path outside root: %s", rel)}
+	}
+
+	if member != "" {
+		return real + ":" + member, nil
+	}
+	return real, nil
+}
+
+// resolveFile is resolve for paths that must be files the validator accepts.
+// Binary files are only accepted when allowBinary is set.
+func (s *Server) resolveFile(rel string, allowBinary bool) (string, error) {
+	if rel == "" {
+		return "", &httpError{http.StatusBadRequest, "missing path"}
+	}
+
+	full, err := s.resolve(rel)
+	if err != nil {
+		return "", err
+	}
+	validator := s.validator
+	if allowBinary {
+		validator = s.binary
+	}
+	if err := validator.Validate(full); err != nil {
+		return "", &httpError{http.StatusForbidden, strings.Replace(err.Error(), full, rel, 1)}
+	}
+	return full, nil
+}
+
+// redact renders err for clients with paths under the root made relative,
+// since errors from the file system carry the full path.
+func (s *Server) redact(err error) string {
+	if s.root == string(filepath.Separator) {
+		return err.Error()
+	}
+	msg := strings.ReplaceAll(err.Error(), s.root+string(filepath.Separator), "")
+	return strings.ReplaceAll(msg, s.root, ".")
+}
+
+// relative turns a resolved path back into the form clients use.
+func (s *Server) relative(full string) string {
+	rel, err := filepath.Rel(s.root, full)
+	if err != nil {
+		return full
+	}
+	return filepath.ToSlash(rel)
+}
\ No newline at end of file
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/samnart1/GoLang/006reader/internal/logger"
+	"github.com/samnart1/GoLang/006reader/internal/reader"
+)
+
+// heartbeat keeps idle event streams from being closed by proxies
+const heartbeat = 15 * time.Second
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize: 1024,
+	WriteBufferSize: 4096,
+}
+
+// handleWatch follows a file and streams its WatchEvents, over a WebSocket
+// when the client asks to upgrade and as Server-Sent Events otherwise.
+func (s *Server) handleWatch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	full, err := s.resolveFile(query.Get("path"), false)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	if _, member := reader.SplitArchivePath(full); member != "" {
+		s.writeError(w, &httpError{http.StatusBadRequest, "archive members cannot be watched"})
+		return
+	}
+
+	watcher := reader.NewWatcher(&reader.WatchConfig{
+		FilePath: full,
+		TailMode: query.Get("tail") == "true",
+		PollInterval: s.config.PollInterval,
+		BufferSize: s.config.BufferSize,
+		Encoding: s.config.Encoding,
+		Poll: query.Get("poll") == "true",
+	})
+	if err := watcher.Start(); err != nil {
+		s.writeError(w, fmt.Errorf("failed to start watcher: %w", err))
+		return
+	}
+	defer watcher.Stop()
+
+	rel := s.relative(full)
+	if websocket.IsWebSocketUpgrade(r) {
+		s.streamWebSocket(w, r, watcher, rel)
+	} else {
+		s.streamEvents(w, r, watcher, rel)
+	}
+}
+
+// streamEvents writes each WatchEvent as a Server-Sent Event named after its
+// type, with the event as JSON data.
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request, watcher *reader.Watcher, rel string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-watcher.Events():
+			event.File = rel
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case err := <-watcher.Errors():
+			s.log.Error("Watcher error", logger.String("file", rel), logger.Error(err))
+			data, _ := json.Marshal(map[string]string{"error": s.redact(err)})
+			fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+			flusher.Flush()
+
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case <-r.Context().Done():
+			return
+
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// streamWebSocket sends each WatchEvent as a JSON text message. Messages from
+// the client are ignored; reading them only notices when it goes away.
+func (s *Server) streamWebSocket(w http.ResponseWriter, r *http.Request, watcher *reader.Watcher, rel string) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has already replied
+		s.log.Error("WebSocket upgrade failed", logger.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-watcher.Events():
+			event.File = rel
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+
+		case err := <-watcher.Errors():
+			s.log.Error("Watcher error", logger.String("file", rel), logger.Error(err))
+			if err := conn.WriteJSON(map[string]string{"type": "error", "error": s.redact(err)}); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(heartbeat)); err != nil {
+				return
+			}
+
+		case <-closed:
+			return
+
+		case <-s.done:
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+				time.Now().Add(time.Second))
+			return
+		}
+	}
+}
\ No newline at end of file
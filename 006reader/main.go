@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -25,6 +26,10 @@ func main() {
 	cmd.SetVersion(version, commit, date)
 
 	if err := cmd.Execute(); err != nil {
+		if errors.Is(err, cmd.ErrFilesDiffer) {
+			log.Sync()
+			os.Exit(1)
+		}
 		log.Error("Application failed", logger.Error(err))
 		os.Exit(1)
 	}
@@ -18,7 +18,9 @@ var (
 	inputFile 	string
 	outputFile	string
 	concurrent 	int
+	perHost		int
 	rateLimit	time.Duration
+	retries		int
 )
 
 var batchCmd = &cobra.Command{
@@ -31,7 +33,8 @@ var batchCmd = &cobra.Command{
 		Examples:
 			webscraper batch --input urls.txt
 			webscraper batch --input urls.txt --output results.json
-			webscraper batch --input urls.txt --concurrent 5 --rate-limit 200ms`,
+			webscraper batch --input urls.txt --concurrent 5 --rate-limit 200ms
+			webscraper batch --input urls.txt --per-host 1 --retries 5`,
 		
 	RunE: runBatch,
 }
@@ -42,7 +45,9 @@ func init() {
 	batchCmd.Flags().StringVarP(&inputFile, "input", "i", "", "Input file containing URLs (required)")
 	batchCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file for results (default: stdout)")
 	batchCmd.Flags().IntVarP(&concurrent, "concurrent", "c", 0, "Number of concurrent workers (default: from config)")
-	batchCmd.Flags().DurationVar(&rateLimit, "rate-limit", 0, "Rate limit between requests (default: from config)")
+	batchCmd.Flags().IntVar(&perHost, "per-host", 0, "Maximum concurrent requests per host (default: from config)")
+	batchCmd.Flags().DurationVar(&rateLimit, "rate-limit", 0, "Delay between requests to the same host (default: from config)")
+	batchCmd.Flags().IntVar(&retries, "retries", -1, "Retry attempts for network errors, 429 and 5xx (default: from config)")
 	batchCmd.Flags().StringVarP(&outputFormat, "format", "f", "json", "Output format: json, pretty")
 	batchCmd.Flags().BoolVar(&includeLinks, "links", true, "Include links in output")
 	batchCmd.Flags().BoolVar(&includeImages, "images", true, "Include images in output")
@@ -69,18 +74,28 @@ func runBatch(cmd *cobra.Command, args []string) error {
 		workers = concurrent
 	}
 
+	hostLimit := cfg.MaxPerHost
+	if perHost > 0 {
+		hostLimit = perHost
+	}
+
 	delay := cfg.RateLimit 
 	if rateLimit > 0 {
 		delay = rateLimit
 	}
 
+	if retries >= 0 {
+		cfg.RetryAttempts = retries
+	}
+
 	s := scraper.New(cfg)
+	limiter := scraper.NewHostLimiter(hostLimit, delay)
 
 	options := types.DefaultScrapeOptions()
 	options.IncludeLinks = includeLinks
 	options.IncludeImages = includeImages
 
-	batchResult := runBatchScraping(s, urls, options, workers, delay)
+	batchResult := runBatchScraping(s, urls, options, workers, limiter)
 
 	if outputFile != "" {
 		return writeResultToFile(batchResult, outputFile)
@@ -116,7 +131,7 @@ func readURLsFromFile(filename string) ([]string, error) {
 	return urls, scanner.Err()
 }
 
-func runBatchScraping(s *scraper.Scraper, urls []string, options types.ScrapeOptions, workers int, delay time.Duration) types.BatchScrapedResult {
+func runBatchScraping(s *scraper.Scraper, urls []string, options types.ScrapeOptions, workers int, limiter *scraper.HostLimiter) types.BatchScrapedResult {
 	startTime := time.Now()
 
 	urlChan := make(chan string, len(urls))
@@ -130,7 +145,7 @@ func runBatchScraping(s *scraper.Scraper, urls []string, options types.ScrapeOpt
 	var wg sync.WaitGroup
 	for i := 0; i < workers; i++ {
 		wg.Add(1)
-		go worker(s, urlChan, resultChan, options, delay, &wg)
+		go worker(s, urlChan, resultChan, options, limiter, &wg)
 	}
 
 	go func() {
@@ -154,7 +169,7 @@ func runBatchScraping(s *scraper.Scraper, urls []string, options types.ScrapeOpt
 			if !result.Success {
 				status = "✗"
 			}
-			fmt.Printf("%s %s (%v)\n", status, result.URL, result.Duration)
+			fmt.Printf("%s %s (%v, %d attempts)\n", status, result.URL, result.Duration, result.Attempts)
 		}
 	}
 
@@ -171,16 +186,15 @@ func runBatchScraping(s *scraper.Scraper, urls []string, options types.ScrapeOpt
 	}
 }
 
-func worker(s *scraper.Scraper, urlChan <-chan string, resultChan chan<- types.ScrapeResult, options types.ScrapeOptions, delay time.Duration, wg *sync.WaitGroup) {
+func worker(s *scraper.Scraper, urlChan <-chan string, resultChan chan<- types.ScrapeResult, options types.ScrapeOptions, limiter *scraper.HostLimiter, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	for url := range urlChan {
+		release := limiter.Acquire(url)
 		result := s.ScrapeURL(url, options)
-		resultChan <- result
+		release()
 
-		if delay > 0 {
-			time.Sleep(delay)
-		}
+		resultChan <- result
 	}
 }
 
@@ -216,7 +230,11 @@ func outputBatchResults(batchResult types.BatchScrapedResult) error {
 			if !result.Success {
 				status = "✗"
 			}
-			fmt.Printf("%s %s - %s (%v)\n", status, result.URL, result.Title, result.Duration)
+			if result.Success {
+				fmt.Printf("%s %s - %s (%v)\n", status, result.URL, result.Title, result.Duration)
+			} else {
+				fmt.Printf("%s %s - %s after %d attempts: %s\n", status, result.URL, result.FailureReason, result.Attempts, result.Error)
+			}
 		}
 		return nil
 	default:
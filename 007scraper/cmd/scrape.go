@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/samnart1/golang/007scrapper/internal/scraper"
@@ -17,6 +18,15 @@ var (
 	includeImages	bool
 	customTimeout	time.Duration
 	customAgent		string
+	followLinks		bool
+	maxDepth		int
+	maxPages		int
+	allowedHosts	[]string
+	pathPrefix		string
+	urlPattern		string
+	ignoreRobots	bool
+	schemaFile		string
+	scrapeRetries	int
 )
 
 var scrapeCmd = &cobra.Command{
@@ -28,7 +38,9 @@ var scrapeCmd = &cobra.Command{
 			webscraper scrape https://example.com
 			webscraper scrape https://example.com --format json
 			webscraper scrape https://example.com --no-links --no-images
-			webscraper scrape https://example.com --timeout 60s --agent "Custom Bot"`,
+			webscraper scrape https://example.com --timeout 60s --agent "Custom Bot"
+			webscraper scrape https://example.com --follow --depth 2 --prefix /docs
+			webscraper scrape https://example.com --schema products.yaml`,
 	
 	Args: cobra.ExactArgs(1),
 	RunE: runScrape,
@@ -42,6 +54,15 @@ func init() {
 	scrapeCmd.Flags().BoolVar(&includeImages, "images", true, "Include images in output")
 	scrapeCmd.Flags().DurationVar(&customTimeout, "timeout", 0, "Custom timeout (e.g., 30s, 1m)")
 	scrapeCmd.Flags().StringVar(&customAgent, "agent", "", "Custom user agent")
+	scrapeCmd.Flags().BoolVar(&followLinks, "follow", false, "Crawl links breadth-first and output a site map")
+	scrapeCmd.Flags().IntVar(&maxDepth, "depth", 1, "Maximum link depth to follow from the start URL")
+	scrapeCmd.Flags().IntVar(&maxPages, "max-pages", 100, "Maximum number of pages to crawl (0 for no limit)")
+	scrapeCmd.Flags().StringSliceVar(&allowedHosts, "allow-host", nil, "Additional hosts to crawl (e.g., docs.example.com, *.example.com)")
+	scrapeCmd.Flags().StringVar(&pathPrefix, "prefix", "", "Only follow links whose path starts with this prefix")
+	scrapeCmd.Flags().StringVar(&urlPattern, "match", "", "Only follow links matching this regular expression")
+	scrapeCmd.Flags().BoolVar(&ignoreRobots, "ignore-robots", false, "Do not fetch or obey robots.txt")
+	scrapeCmd.Flags().IntVar(&scrapeRetries, "retries", -1, "Retry attempts for network errors, 429 and 5xx (default: from config)")
+	scrapeCmd.Flags().StringVar(&schemaFile, "schema", "", "YAML or JSON extraction schema for structured records")
 }
 
 func runScrape(cmd *cobra.Command, args []string) error {
@@ -51,6 +72,10 @@ func runScrape(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Scraping URL: %s\n", url)
 	}
 
+	if scrapeRetries >= 0 {
+		cfg.RetryAttempts = scrapeRetries
+	}
+
 	s := scraper.New(cfg)
 
 	options := types.DefaultScrapeOptions()
@@ -65,6 +90,40 @@ func runScrape(cmd *cobra.Command, args []string) error {
 		options.UserAgent = customAgent
 	}
 
+	if schemaFile != "" {
+		schema, err := scraper.LoadSchema(schemaFile)
+		if err != nil {
+			return fmt.Errorf("failed to load schema: %v", err)
+		}
+		options.Schema = schema
+	}
+
+	if followLinks {
+		options.FollowLinks = true
+		options.MaxDepth = maxDepth
+		options.MaxPages = maxPages
+		options.AllowedHosts = allowedHosts
+		options.PathPrefix = pathPrefix
+		options.URLPattern = urlPattern
+		options.IgnoreRobots = ignoreRobots
+
+		siteMap, err := s.Crawl(url, options)
+		if err != nil {
+			return err
+		}
+
+		switch outputFormat {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", " ")
+			return encoder.Encode(siteMap)
+		case "pretty":
+			return outputSiteMap(siteMap)
+		default:
+			return fmt.Errorf("unsupported output format: %s", outputFormat)
+		}
+	}
+
 	result := s.ScrapeURL(url, options)
 
 	switch outputFormat {
@@ -93,10 +152,14 @@ func outputPretty(result types.ScrapeResult) error {
 		if result.Error != "" {
 			fmt.Printf("Error: %s\n", result.Error)
 		}
+		fmt.Printf("Reason: %s after %d attempts\n", result.FailureReason, result.Attempts)
 		return nil
 	}
 
 	fmt.Printf("Duration: %v\n", result.Duration)
+	if result.Attempts > 1 {
+		fmt.Printf("Attempts: %d\n", result.Attempts)
+	}
 	fmt.Printf("Scraped: %s\n\n", result.ScrapedAt.Format(time.RFC3339))
 
 	fmt.Printf("Title: %s\n", result.Title)
@@ -116,6 +179,17 @@ func outputPretty(result types.ScrapeResult) error {
 		}
 	}
 
+	if len(result.Records) > 0 {
+		fmt.Printf("\nRecords (%d):\n", len(result.Records))
+		for _, record := range result.Records {
+			data, err := json.MarshalIndent(record, "	", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Printf("	%s\n", data)
+		}
+	}
+
 	if len(result.Links) > 0 {
 		fmt.Printf("\nLnks (%d):\n", len(result.Links))
 		for i, link := range result.Links {
@@ -147,4 +221,39 @@ func outputPretty(result types.ScrapeResult) error {
 	}
 
 	return nil
+}
+
+func outputSiteMap(siteMap types.SiteMap) error {
+	fmt.Printf("Site Map: %s\n", siteMap.Root)
+	fmt.Printf("==========\n")
+	fmt.Printf("Pages: %d (max depth %d)\n", siteMap.Total, siteMap.MaxDepth)
+	fmt.Printf("Successful: %d\n", siteMap.Success)
+	fmt.Printf("Failed: %d\n", siteMap.Failed)
+	fmt.Printf("Duration: %v\n\n", siteMap.Duration)
+
+	children := make(map[string][]types.Page)
+	for _, page := range siteMap.Pages {
+		children[page.Parent] = append(children[page.Parent], page)
+	}
+	printPages(children, "")
+
+	if len(siteMap.Disallowed) > 0 {
+		fmt.Printf("\nDisallowed by robots.txt (%d):\n", len(siteMap.Disallowed))
+		for _, url := range siteMap.Disallowed {
+			fmt.Printf("	%s\n", url)
+		}
+	}
+
+	return nil
+}
+
+func printPages(children map[string][]types.Page, parent string) {
+	for _, page := range children[parent] {
+		status := "✔"
+		if !page.Result.Success {
+			status = "✗"
+		}
+		fmt.Printf("%s%s %s - %s (HTTP %d)\n", strings.Repeat("	", page.Depth), status, page.URL, page.Result.Title, page.Result.StatusCode)
+		printPages(children, page.URL)
+	}
 }
\ No newline at end of file
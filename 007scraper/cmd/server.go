@@ -24,6 +24,7 @@ var serverCmd = &cobra.Command{
 		GET 	/health			- Health check
 		POST 	/scrape			- Scrape a single URL
 		POST	/scrape/batch	- scrape multiple URLs
+		POST	/crawl			- crawl links and return a site map
 		
 	Examples:
 		webscraper server
@@ -59,6 +60,7 @@ func runServer(cmd *cobra.Command, args []string) error {
 	fmt.Printf("	GET  /health		- Health check/n")
 	fmt.Printf("	POST /scrape		- Scrape a single URL\n")
 	fmt.Printf("	POST /scrape/batch	- Scrape multiple URLs\n")
+	fmt.Printf("	POST /crawl		- Crawl links and return a site map\n")
 	fmt.Printf("\nPress Ctrl+C to stop the server\n\n")
 
 	go func() {
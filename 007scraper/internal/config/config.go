@@ -14,7 +14,10 @@ type Config struct {
 	ServerHost	string
 
 	MaxConcurrent	int
+	MaxPerHost		int
 	RetryAttempts	int
+	RetryBaseDelay	time.Duration
+	RetryMaxDelay	time.Duration
 	RateLimit		time.Duration
 }
 
@@ -25,7 +28,10 @@ func Load() *Config {
 		ServerPort: "8080",
 		ServerHost: "localhost",
 		MaxConcurrent: 10,
+		MaxPerHost: 2,
 		RetryAttempts: 3,
+		RetryBaseDelay: 500 * time.Millisecond,
+		RetryMaxDelay: 30 * time.Second,
 		RateLimit: 100 * time.Millisecond,
 	}
 
@@ -53,12 +59,34 @@ func Load() *Config {
 		}
 	}
 
-	if retryAttempts := os.Getenv("RETRY_ATTEMPS"); retryAttempts != "" {
-		if n, err := strconv.Atoi(retryAttempts); err == nil {
+	if maxPerHost := os.Getenv("MAX_PER_HOST"); maxPerHost != "" {
+		if n, err := strconv.Atoi(maxPerHost); err == nil && n > 0 {
+			cfg.MaxPerHost = n
+		}
+	}
+
+	retryAttempts := os.Getenv("RETRY_ATTEMPTS")
+	if retryAttempts == "" {
+		retryAttempts = os.Getenv("RETRY_ATTEMPS")
+	}
+	if retryAttempts != "" {
+		if n, err := strconv.Atoi(retryAttempts); err == nil && n >= 0 {
 			cfg.RetryAttempts = n
 		}
 	}
 
+	if baseDelay := os.Getenv("RETRY_BASE_DELAY"); baseDelay != "" {
+		if d, err := time.ParseDuration(baseDelay); err == nil && d > 0 {
+			cfg.RetryBaseDelay = d
+		}
+	}
+
+	if maxDelay := os.Getenv("RETRY_MAX_DELAY"); maxDelay != "" {
+		if d, err := time.ParseDuration(maxDelay); err == nil && d > 0 {
+			cfg.RetryMaxDelay = d
+		}
+	}
+
 	if rateLimit := os.Getenv("RATE_LIMIT"); rateLimit != "" {
 		if d, err := time.ParseDuration(rateLimit); err == nil {
 			cfg.RateLimit = d
@@ -0,0 +1,274 @@
+package scraper
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/samnart1/golang/007scrapper/pkg/types"
+)
+
+type crawlTarget struct {
+	url		*url.URL
+	depth	int
+	parent	string
+}
+
+type crawler struct {
+	scraper		*Scraper
+	options		types.ScrapeOptions
+	hosts		[]string
+	pattern		*regexp.Regexp
+	robots		map[string]*robots
+	lastFetch	map[string]time.Time
+	visited		map[string]bool
+	// crawled holds the pages fetched so far by the URL they ended up at
+	// after redirects
+	crawled		map[string]bool
+}
+
+func (s *Scraper) Crawl(rawURL string, options types.ScrapeOptions) (types.SiteMap, error) {
+	root, err := url.Parse(rawURL)
+	if err != nil || (root.Scheme != "http" && root.Scheme != "https") || root.Host == "" {
+		return types.SiteMap{}, fmt.Errorf("invalid URL: %s", rawURL)
+	}
+
+	c := &crawler{
+		scraper: s,
+		options: options,
+		robots: make(map[string]*robots),
+		lastFetch: make(map[string]time.Time),
+		visited: make(map[string]bool),
+		crawled: make(map[string]bool),
+	}
+
+	if options.URLPattern != "" {
+		if c.pattern, err = regexp.Compile(options.URLPattern); err != nil {
+			return types.SiteMap{}, fmt.Errorf("invalid URL pattern: %v", err)
+		}
+	}
+
+	c.hosts = []string{strings.ToLower(root.Hostname())}
+	for _, host := range options.AllowedHosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			c.hosts = append(c.hosts, host)
+		}
+	}
+
+	maxDepth := options.MaxDepth
+	if !options.FollowLinks || maxDepth < 0 {
+		maxDepth = 0
+	}
+
+	return c.run(root, maxDepth), nil
+}
+
+func (c *crawler) run(root *url.URL, maxDepth int) types.SiteMap {
+	startTime := time.Now()
+	siteMap := types.SiteMap{
+		Root: normalizeURL(root),
+		MaxDepth: maxDepth,
+		StartTime: startTime,
+	}
+
+	fetchOptions := c.options
+	fetchOptions.IncludeLinks = true
+
+	queue := []crawlTarget{{url: root}}
+	c.visited[siteMap.Root] = true
+
+	for len(queue) > 0 {
+		if c.options.MaxPages > 0 && len(siteMap.Pages) >= c.options.MaxPages {
+			break
+		}
+
+		target := queue[0]
+		queue = queue[1:]
+		pageURL := normalizeURL(target.url)
+
+		// an earlier redirect may already have landed on this page
+		if c.crawled[pageURL] {
+			continue
+		}
+
+		rules := c.robotsFor(target.url)
+		if !c.options.IgnoreRobots && !rules.allowed(target.url) {
+			siteMap.Disallowed = append(siteMap.Disallowed, pageURL)
+			continue
+		}
+
+		c.wait(target.url, rules.delay)
+		result := c.scraper.ScrapeURL(pageURL, fetchOptions)
+		links := result.Links
+
+		c.crawled[pageURL] = true
+		if final, err := url.Parse(result.FinalURL); err == nil && result.FinalURL != "" {
+			finalURL := normalizeURL(final)
+			if finalURL != pageURL {
+				if c.crawled[finalURL] {
+					continue
+				}
+				c.crawled[finalURL] = true
+				c.visited[finalURL] = true
+
+				// a redirect may leave the scope or land somewhere robots.txt
+				// forbids; such pages are dropped like links that never
+				// qualified
+				if !c.inScope(final) {
+					continue
+				}
+				if !c.options.IgnoreRobots && !c.robotsFor(final).allowed(final) {
+					siteMap.Disallowed = append(siteMap.Disallowed, finalURL)
+					continue
+				}
+			}
+		}
+
+		if !c.options.IncludeLinks {
+			result.Links = nil
+		}
+
+		siteMap.Pages = append(siteMap.Pages, types.Page{
+			URL: pageURL,
+			Depth: target.depth,
+			Parent: target.parent,
+			Result: result,
+		})
+		if result.Success {
+			siteMap.Success++
+		} else {
+			siteMap.Failed++
+		}
+
+		if target.depth >= maxDepth {
+			continue
+		}
+
+		for _, link := range links {
+			if hasRel(link.Rel, "nofollow") {
+				continue
+			}
+
+			next, err := url.Parse(link.URL)
+			if err != nil || !c.inScope(next) {
+				continue
+			}
+
+			key := normalizeURL(next)
+			if c.visited[key] {
+				continue
+			}
+			c.visited[key] = true
+
+			queue = append(queue, crawlTarget{url: next, depth: target.depth + 1, parent: pageURL})
+		}
+	}
+
+	siteMap.Total = len(siteMap.Pages)
+	siteMap.EndTime = time.Now()
+	siteMap.Duration = siteMap.EndTime.Sub(startTime)
+	return siteMap
+}
+
+func (c *crawler) inScope(u *url.URL) bool {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+
+	if !c.hostAllowed(strings.ToLower(u.Hostname())) {
+		return false
+	}
+
+	if c.options.PathPrefix != "" && !strings.HasPrefix(u.Path, c.options.PathPrefix) {
+		return false
+	}
+
+	if c.pattern != nil && !c.pattern.MatchString(normalizeURL(u)) {
+		return false
+	}
+
+	return true
+}
+
+func (c *crawler) hostAllowed(host string) bool {
+	for _, allowed := range c.hosts {
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if host == suffix || strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *crawler) robotsFor(u *url.URL) *robots {
+	if c.options.IgnoreRobots {
+		return &robots{}
+	}
+
+	key := strings.ToLower(u.Scheme + "://" + u.Host)
+	if rules, ok := c.robots[key]; ok {
+		return rules
+	}
+
+	rules := c.scraper.fetchRobots(u, c.options.UserAgent)
+	c.robots[key] = rules
+	return rules
+}
+
+// wait spaces out requests to the same host by the larger of the
+// configured rate limit and the robots.txt crawl-delay.
+func (c *crawler) wait(u *url.URL, crawlDelay time.Duration) {
+	delay := c.scraper.config.RateLimit
+	if crawlDelay > delay {
+		delay = crawlDelay
+	}
+
+	host := strings.ToLower(u.Host)
+	if last, ok := c.lastFetch[host]; ok && delay > 0 {
+		if remaining := delay - time.Since(last); remaining > 0 {
+			time.Sleep(remaining)
+		}
+	}
+	c.lastFetch[host] = time.Now()
+}
+
+func hasRel(rel, value string) bool {
+	for _, field := range strings.Fields(rel) {
+		if strings.EqualFold(field, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeURL lowercases scheme and host, drops default ports and
+// fragments and sorts the query so equivalent URLs compare equal.
+func normalizeURL(u *url.URL) string {
+	n := *u
+	n.Scheme = strings.ToLower(n.Scheme)
+	n.Host = strings.ToLower(n.Host)
+	n.Fragment = ""
+	n.RawFragment = ""
+
+	if port := n.Port(); (n.Scheme == "http" && port == "80") || (n.Scheme == "https" && port == "443") {
+		n.Host = strings.TrimSuffix(n.Host, ":"+port)
+	}
+
+	if n.Path == "" {
+		n.Path = "/"
+		n.RawPath = ""
+	}
+
+	if n.RawQuery != "" {
+		n.RawQuery = n.Query().Encode()
+	}
+
+	return n.String()
+}
\ No newline at end of file
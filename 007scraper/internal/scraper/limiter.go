@@ -0,0 +1,78 @@
+package scraper
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HostLimiter caps concurrent requests per host and spaces out the start
+// of consecutive requests to the same host by a fixed delay.
+type HostLimiter struct {
+	maxPerHost	int
+	delay		time.Duration
+	mu			sync.Mutex
+	hosts		map[string]*hostSlot
+}
+
+type hostSlot struct {
+	slots	chan struct{}
+	mu		sync.Mutex
+	next	time.Time
+}
+
+func NewHostLimiter(maxPerHost int, delay time.Duration) *HostLimiter {
+	if maxPerHost <= 0 {
+		maxPerHost = 1
+	}
+
+	return &HostLimiter{
+		maxPerHost: maxPerHost,
+		delay: delay,
+		hosts: make(map[string]*hostSlot),
+	}
+}
+
+// Acquire blocks until a request to the URL's host may start and returns
+// the function that releases the slot.
+func (l *HostLimiter) Acquire(rawURL string) func() {
+	slot := l.slot(hostKey(rawURL))
+	slot.slots <- struct{}{}
+
+	slot.mu.Lock()
+	now := time.Now()
+	start := now
+	if slot.next.After(now) {
+		start = slot.next
+	}
+	slot.next = start.Add(l.delay)
+	slot.mu.Unlock()
+
+	if wait := start.Sub(now); wait > 0 {
+		time.Sleep(wait)
+	}
+
+	return func() {
+		<-slot.slots
+	}
+}
+
+func (l *HostLimiter) slot(host string) *hostSlot {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	slot, ok := l.hosts[host]
+	if !ok {
+		slot = &hostSlot{slots: make(chan struct{}, l.maxPerHost)}
+		l.hosts[host] = slot
+	}
+	return slot
+}
+
+func hostKey(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return strings.ToLower(u.Host)
+	}
+	return rawURL
+}
\ No newline at end of file
@@ -0,0 +1,142 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/samnart1/golang/007scrapper/pkg/types"
+)
+
+const maxDrainSize = 64 * 1024
+
+// fetch retries network errors, 429 and 5xx responses with exponential
+// backoff and jitter, waiting for Retry-After when the server sends one.
+// On failure it fills in the error, failure reason and status of result.
+func (s *Scraper) fetch(rawURL string, options types.ScrapeOptions, result *types.ScrapeResult) (*http.Response, bool) {
+	if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		result.Attempts = 1
+		result.Error = fmt.Sprintf("failed to create request: invalid URL %q", rawURL)
+		result.FailureReason = types.FailureInvalidRequest
+		return nil, false
+	}
+
+	for attempt := 1; ; attempt++ {
+		result.Attempts = attempt
+
+		req, err := http.NewRequest("GET", rawURL, nil)
+		if err != nil {
+			result.Error = fmt.Sprintf("failed to create request: %v", err)
+			result.FailureReason = types.FailureInvalidRequest
+			return nil, false
+		}
+
+		req.Header.Set("User-Agent", options.UserAgent)
+
+		for key, value := range options.CustomeHeaders {
+			req.Header.Set(key, value)
+		}
+
+		var retryAfter time.Duration
+		var hasRetryAfter bool
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			result.StatusCode = 0
+			result.Error = fmt.Sprintf("failed to fetch URL: %v", err)
+			result.FailureReason = networkFailure(err)
+		} else {
+			result.StatusCode = resp.StatusCode
+
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				result.Error = ""
+				result.FailureReason = ""
+				return resp, true
+			}
+
+			result.Error = fmt.Sprintf("HTTP error: %s", resp.Status)
+
+			switch {
+			case resp.StatusCode == http.StatusTooManyRequests:
+				result.FailureReason = types.FailureRateLimited
+			case resp.StatusCode >= 500:
+				result.FailureReason = types.FailureServerError
+			default:
+				result.FailureReason = types.FailureHTTPError
+				resp.Body.Close()
+				return nil, false
+			}
+
+			retryAfter, hasRetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainSize))
+			resp.Body.Close()
+		}
+
+		if attempt > s.config.RetryAttempts {
+			return nil, false
+		}
+
+		wait := s.backoff(attempt)
+		if hasRetryAfter {
+			if retryAfter > s.config.RetryMaxDelay {
+				result.Error = fmt.Sprintf("%s (Retry-After %v exceeds max retry delay %v)", result.Error, retryAfter, s.config.RetryMaxDelay)
+				return nil, false
+			}
+			wait = retryAfter
+		}
+
+		time.Sleep(wait)
+	}
+}
+
+// backoff doubles the base delay per attempt up to the maximum and picks
+// a random wait in the upper half so concurrent retries spread out.
+func (s *Scraper) backoff(attempt int) time.Duration {
+	delay := s.config.RetryBaseDelay
+	for i := 1; i < attempt && delay < s.config.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > s.config.RetryMaxDelay {
+		delay = s.config.RetryMaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if when, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(when); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+func networkFailure(err error) string {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return types.FailureTimeout
+	}
+	return types.FailureNetwork
+}
\ No newline at end of file
@@ -0,0 +1,194 @@
+package scraper
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxRobotsSize = 512 * 1024
+
+type robots struct {
+	rules	[]robotsRule
+	delay	time.Duration
+}
+
+type robotsRule struct {
+	pattern	*regexp.Regexp
+	length	int
+	allow	bool
+}
+
+type robotsGroup struct {
+	agents	[]string
+	rules	[]robotsRule
+	delay	time.Duration
+}
+
+// fetchRobots follows RFC 9309: a missing robots.txt (4xx) allows
+// everything, while an unreachable one (5xx or network error) disallows
+// everything.
+func (s *Scraper) fetchRobots(u *url.URL, userAgent string) *robots {
+	robotsURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+
+	req, err := http.NewRequest("GET", robotsURL.String(), nil)
+	if err != nil {
+		return disallowAll()
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return disallowAll()
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return parseRobots(io.LimitReader(resp.Body, maxRobotsSize), userAgent)
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return &robots{}
+	default:
+		return disallowAll()
+	}
+}
+
+func disallowAll() *robots {
+	return &robots{
+		rules: []robotsRule{{pattern: regexp.MustCompile("^/"), length: 1}},
+	}
+}
+
+func parseRobots(r io.Reader, userAgent string) *robots {
+	var groups []*robotsGroup
+	var current *robotsGroup
+	inRules := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if current == nil || inRules {
+				current = &robotsGroup{}
+				groups = append(groups, current)
+				inRules = false
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			if current == nil {
+				continue
+			}
+			inRules = true
+			if value == "" {
+				continue
+			}
+			if rule, ok := newRobotsRule(value, key == "allow"); ok {
+				current.rules = append(current.rules, rule)
+			}
+		case "crawl-delay":
+			if current == nil {
+				continue
+			}
+			inRules = true
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				current.delay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+
+	product := strings.ToLower(userAgent)
+	if idx := strings.IndexAny(product, "/ "); idx != -1 {
+		product = product[:idx]
+	}
+
+	matched := ""
+	for _, group := range groups {
+		for _, agent := range group.agents {
+			if agent != "*" && product != "" && strings.Contains(product, agent) && len(agent) > len(matched) {
+				matched = agent
+			}
+		}
+	}
+	if matched == "" {
+		matched = "*"
+	}
+
+	result := &robots{}
+	for _, group := range groups {
+		for _, agent := range group.agents {
+			if agent == matched {
+				result.rules = append(result.rules, group.rules...)
+				if group.delay > result.delay {
+					result.delay = group.delay
+				}
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+func newRobotsRule(path string, allow bool) (robotsRule, bool) {
+	anchored := strings.HasSuffix(path, "$")
+	trimmed := strings.TrimSuffix(path, "$")
+
+	parts := strings.Split(trimmed, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return robotsRule{}, false
+	}
+
+	return robotsRule{pattern: pattern, length: len(path), allow: allow}, true
+}
+
+// allowed picks the longest matching rule, preferring allow on ties.
+func (r *robots) allowed(u *url.URL) bool {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	allowed := true
+	longest := -1
+	for _, rule := range r.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		if rule.length > longest || (rule.length == longest && rule.allow) {
+			longest = rule.length
+			allowed = rule.allow
+		}
+	}
+
+	return allowed
+}
\ No newline at end of file
@@ -0,0 +1,269 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
+	"github.com/samnart1/golang/007scrapper/pkg/types"
+	"golang.org/x/net/html"
+	"gopkg.in/yaml.v3"
+)
+
+type fieldRule struct {
+	name		string
+	css			cascadia.SelectorGroup
+	xpath		*xpath.Expr
+	attr		string
+	regex		*regexp.Regexp
+	kind		string
+	multiple	bool
+	fields		[]*fieldRule
+}
+
+// LoadSchema reads an extraction schema from a YAML or JSON file. YAML
+// is decoded generically and re-encoded so both formats share the JSON
+// field names and the string shorthand for selectors.
+func LoadSchema(path string) (*types.Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %v", err)
+	}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %v", err)
+	}
+
+	var schema types.Schema
+	if err := json.Unmarshal(encoded, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %v", err)
+	}
+
+	if err := ValidateSchema(&schema); err != nil {
+		return nil, err
+	}
+
+	return &schema, nil
+}
+
+func ValidateSchema(schema *types.Schema) error {
+	_, err := compileSchema(schema)
+	return err
+}
+
+func compileSchema(schema *types.Schema) (*fieldRule, error) {
+	root := types.SchemaField{
+		Selector: schema.Selector,
+		XPath: schema.XPath,
+		Multiple: true,
+		Fields: schema.Fields,
+	}
+
+	if len(schema.Fields) == 0 {
+		return nil, fmt.Errorf("invalid schema: no fields defined")
+	}
+
+	return compileField("schema", root)
+}
+
+func compileField(name string, field types.SchemaField) (*fieldRule, error) {
+	rule := &fieldRule{
+		name: name,
+		attr: strings.ToLower(field.Attr),
+		kind: strings.ToLower(field.Type),
+		multiple: field.Multiple,
+	}
+
+	if field.Selector != "" && field.XPath != "" {
+		return nil, fmt.Errorf("invalid field %q: selector and xpath are mutually exclusive", name)
+	}
+
+	var err error
+	if field.Selector != "" {
+		if rule.css, err = cascadia.ParseGroup(field.Selector); err != nil {
+			return nil, fmt.Errorf("invalid selector for field %q: %v", name, err)
+		}
+	}
+
+	if field.XPath != "" {
+		if rule.xpath, err = xpath.Compile(field.XPath); err != nil {
+			return nil, fmt.Errorf("invalid xpath for field %q: %v", name, err)
+		}
+	}
+
+	if field.Regex != "" {
+		if rule.regex, err = regexp.Compile(field.Regex); err != nil {
+			return nil, fmt.Errorf("invalid regex for field %q: %v", name, err)
+		}
+	}
+
+	switch rule.kind {
+	case "", "string", "int", "float", "bool", "url":
+	default:
+		return nil, fmt.Errorf("invalid type for field %q: %s", name, field.Type)
+	}
+
+	names := make([]string, 0, len(field.Fields))
+	for child := range field.Fields {
+		names = append(names, child)
+	}
+	sort.Strings(names)
+
+	for _, child := range names {
+		path := child
+		if name != "schema" {
+			path = name + "." + child
+		}
+
+		compiled, err := compileField(path, field.Fields[child])
+		if err != nil {
+			return nil, err
+		}
+		compiled.name = child
+		rule.fields = append(rule.fields, compiled)
+	}
+
+	return rule, nil
+}
+
+func (s *Scraper) extractRecords(doc *html.Node, baseURL string, schema *types.Schema) ([]map[string]interface{}, error) {
+	rule, err := compileSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []map[string]interface{}
+	for _, node := range rule.nodes(doc) {
+		records = append(records, s.extractRecord(node, baseURL, rule.fields))
+	}
+
+	return records, nil
+}
+
+func (s *Scraper) extractRecord(node *html.Node, baseURL string, fields []*fieldRule) map[string]interface{} {
+	record := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		record[field.name] = s.extractField(node, baseURL, field)
+	}
+	return record
+}
+
+// extractField returns a single value, or a list when the field is
+// multiple. Values whose regex does not match are dropped.
+func (s *Scraper) extractField(node *html.Node, baseURL string, field *fieldRule) interface{} {
+	values := []interface{}{}
+
+	for _, match := range field.nodes(node) {
+		if len(field.fields) > 0 {
+			values = append(values, s.extractRecord(match, baseURL, field.fields))
+		} else if value, ok := s.fieldValue(match, baseURL, field); ok {
+			values = append(values, value)
+		}
+
+		if !field.multiple && len(values) > 0 {
+			return values[0]
+		}
+	}
+
+	if field.multiple {
+		return values
+	}
+	return nil
+}
+
+func (r *fieldRule) nodes(node *html.Node) []*html.Node {
+	switch {
+	case r.xpath != nil:
+		return htmlquery.QuerySelectorAll(node, r.xpath)
+	case r.css != nil:
+		return cascadia.QueryAll(node, r.css)
+	default:
+		return []*html.Node{node}
+	}
+}
+
+func (s *Scraper) fieldValue(node *html.Node, baseURL string, field *fieldRule) (interface{}, bool) {
+	var text string
+	switch field.attr {
+	case "", "text":
+		text = strings.Join(strings.Fields(htmlquery.InnerText(node)), " ")
+	case "html":
+		text = strings.TrimSpace(htmlquery.OutputHTML(node, false))
+	default:
+		if !htmlquery.ExistsAttr(node, field.attr) {
+			return nil, false
+		}
+		text = strings.TrimSpace(htmlquery.SelectAttr(node, field.attr))
+	}
+
+	if field.regex != nil {
+		match := field.regex.FindStringSubmatch(text)
+		if match == nil {
+			return nil, false
+		}
+		text = match[0]
+		if len(match) > 1 {
+			text = match[1]
+		}
+	}
+
+	return s.convertValue(text, baseURL, field.kind)
+}
+
+// convertValue ignores everything but digits, signs and the decimal
+// point for numbers, so "$1,299.00" converts to 1299.
+func (s *Scraper) convertValue(text, baseURL, kind string) (interface{}, bool) {
+	switch kind {
+	case "int", "float":
+		cleaned := strings.Map(func(r rune) rune {
+			if (r >= '0' && r <= '9') || r == '.' || r == '-' {
+				return r
+			}
+			return -1
+		}, text)
+
+		if kind == "int" {
+			if n, err := strconv.ParseFloat(cleaned, 64); err == nil {
+				return int64(n), true
+			}
+			return nil, false
+		}
+		if n, err := strconv.ParseFloat(cleaned, 64); err == nil {
+			return n, true
+		}
+		return nil, false
+	case "bool":
+		switch strings.ToLower(text) {
+		case "yes", "on":
+			return true, true
+		case "no", "off":
+			return false, true
+		}
+		if b, err := strconv.ParseBool(strings.ToLower(text)); err == nil {
+			return b, true
+		}
+		// "Out of stock" is not a boolean, so it is dropped like a failed
+		// number rather than read as true
+		return nil, false
+	case "url":
+		if text == "" {
+			return nil, false
+		}
+		return s.resolveURL(text, baseURL), true
+	default:
+		return text, true
+	}
+}
\ No newline at end of file
@@ -3,6 +3,7 @@ package scraper
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -33,42 +34,26 @@ func (s *Scraper) ScrapeURL(url string, options types.ScrapeOptions) types.Scrap
 		Success: false,
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		result.Error = fmt.Sprintf("failed to create request: %v", err)
-		result.Duration = time.Since(startTime)
-		return result
-	}
-
-	req.Header.Set("User-Agent", options.UserAgent)
-
-	for key, value := range options.CustomeHeaders {
-		req.Header.Set(key, value)
-	}
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		result.Error = fmt.Sprintf("failed to fetch URL: %v", err)
+	resp, ok := s.fetch(url, options, &result)
+	if !ok {
 		result.Duration = time.Since(startTime)
 		return result
 	}
 	defer resp.Body.Close()
 
-	result.StatusCode = resp.StatusCode
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		result.Error = fmt.Sprintf("HTTP error: %d %s", resp.StatusCode, resp.Status)
-		result.Duration = time.Since(startTime)
-		return result
-	}
-
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
 		result.Error = fmt.Sprintf("failed to parse HTML: %v", err)
+		result.FailureReason = types.FailureParseError
 		result.Duration = time.Since(startTime)
 		return result
 	}
 
+	// relative references resolve against where the page was actually
+	// served from, so redirects and <base href> are honoured
+	result.FinalURL = resp.Request.URL.String()
+	baseURL := s.documentBase(doc, result.FinalURL)
+
 	result.Title = strings.TrimSpace(doc.Find("title").Text())
 	if result.Title == "" {
 		result.Title = "No title found!"
@@ -87,15 +72,26 @@ func (s *Scraper) ScrapeURL(url string, options types.ScrapeOptions) types.Scrap
 	}
 
 	if options.IncludeLinks {
-		result.Links = s.extractLinks(doc, url)
+		result.Links = s.extractLinks(doc, baseURL)
 	}
 
 	if options.IncludeImages {
-		result.Images = s.extractImages(doc, url)
+		result.Images = s.extractImages(doc, baseURL)
 	}
 
 	result.Headers = s.extractHeaders(doc)
 
+	if options.Schema != nil {
+		records, err := s.extractRecords(doc.Nodes[0], baseURL, options.Schema)
+		if err != nil {
+			result.Error = fmt.Sprintf("failed to extract records: %v", err)
+			result.FailureReason = types.FailureExtraction
+			result.Duration = time.Since(startTime)
+			return result
+		}
+		result.Records = records
+	}
+
 	result.Success = true
 	result.Duration = time.Since(startTime)
 	return result
@@ -166,26 +162,25 @@ func (s *Scraper) extractHeaders(doc *goquery.Document) map[string]string {
 	return headers
 }
 
-func (s *Scraper) resolveURL(href, baseURL string) string {
-	if strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") {
-		return href
-	}
-
-	if strings.HasPrefix(href, "//") {
-		return "https:" + href
+// documentBase returns the URL relative references in doc resolve against:
+// the first <base href> if there is one, otherwise the page URL.
+func (s *Scraper) documentBase(doc *goquery.Document, pageURL string) string {
+	if href, exists := doc.Find("base[href]").First().Attr("href"); exists {
+		return s.resolveURL(href, pageURL)
 	}
+	return pageURL
+}
 
-	if strings.HasPrefix(href, "/") {
-		if idx := strings.Index(baseURL[8:], "/"); idx != -1 {
-			return baseURL[:8+idx] + href
-		}
-		return baseURL + href
+func (s *Scraper) resolveURL(href, baseURL string) string {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return href
 	}
 
-	lastSlash := strings.LastIndex(baseURL, "/")
-	if lastSlash > 7 {
-		return baseURL[:lastSlash+1] + href
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return href
 	}
 
-	return baseURL + "/" + href
+	return base.ResolveReference(ref).String()
 }
\ No newline at end of file
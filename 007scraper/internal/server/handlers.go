@@ -31,6 +31,7 @@ type ScrapeRequest struct {
 	Timeout			string				`json:"timeout,omitempty"`
 	UserAgent		string				`json:"user_agent,omitempty"`
 	CustomHeaders	map[string]string	`json:"custom_headers,omitempty"`
+	Schema			*types.Schema		`json:"schema,omitempty"`
 }
 
 type BatchScrapedResult struct {
@@ -41,9 +42,25 @@ type BatchScrapedResult struct {
 	UserAgent		string				`json:"user_agent,omitempty"`
 	CustomHeaders	map[string]string	`json:"custom_headers,omitempty"`
 	Concurrent		int					`json:"concurrent,omitempty"`
+	PerHost			int					`json:"per_host,omitempty"`
 	RateLimit		string				`json:"rate_limit,omitempty"`
 }
 
+type CrawlRequest struct {
+	URL				string				`json:"url"`
+	IncludeLinks	bool				`json:"include_links,omitempty"`
+	IncludeImages	bool				`json:"include_images,omitempty"`
+	Timeout			string				`json:"timeout,omitempty"`
+	UserAgent		string				`json:"user_agent,omitempty"`
+	CustomHeaders	map[string]string	`json:"custom_headers,omitempty"`
+	MaxDepth		int					`json:"max_depth,omitempty"`
+	MaxPages		int					`json:"max_pages,omitempty"`
+	AllowedHosts	[]string			`json:"allowed_hosts,omitempty"`
+	PathPrefix		string				`json:"path_prefix,omitempty"`
+	URLPattern		string				`json:"url_pattern,omitempty"`
+	IgnoreRobots	bool				`json:"ignore_robots,omitempty"`
+}
+
 func (s *Server) HealthHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -95,12 +112,88 @@ func (s *Server) ScrapeHandler(w http.ResponseWriter, r *http.Request) {
 		options.CustomeHeaders = req.CustomHeaders
 	}
 
+	if req.Schema != nil {
+		if err := scraper.ValidateSchema(req.Schema); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		options.Schema = req.Schema
+	}
+
 	result := s.scraper.ScrapeURL(req.URL, options)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(result)
 }
 
+func (s *Server) CrawlHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req CrawlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.URL == "" {
+		http.Error(w, "URL is required", http.StatusBadRequest)
+		return
+	}
+
+	if req.MaxDepth > 5 {
+		http.Error(w, "Maximum depth of 5 allowed per crawl", http.StatusBadRequest)
+		return
+	}
+
+	if req.MaxPages > 500 {
+		http.Error(w, "Maximum 500 pages allowed per crawl", http.StatusBadRequest)
+		return
+	}
+
+	options := types.DefaultScrapeOptions()
+	options.FollowLinks = true
+	options.IncludeLinks = req.IncludeLinks
+	options.IncludeImages = req.IncludeImages
+	options.AllowedHosts = req.AllowedHosts
+	options.PathPrefix = req.PathPrefix
+	options.URLPattern = req.URLPattern
+	options.IgnoreRobots = req.IgnoreRobots
+
+	if req.MaxDepth > 0 {
+		options.MaxDepth = req.MaxDepth
+	}
+
+	if req.MaxPages > 0 {
+		options.MaxPages = req.MaxPages
+	}
+
+	if req.Timeout != "" {
+		if timeout, err := time.ParseDuration(req.Timeout); err == nil {
+			options.Timeout = timeout
+		}
+	}
+
+	if req.UserAgent != "" {
+		options.UserAgent = req.UserAgent
+	}
+
+	if req.CustomHeaders != nil {
+		options.CustomeHeaders = req.CustomHeaders
+	}
+
+	siteMap, err := s.scraper.Crawl(req.URL, options)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(siteMap)
+}
+
 func (s *Server) BatchScrapeHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -146,6 +239,11 @@ func (s *Server) BatchScrapeHandler(w http.ResponseWriter, r *http.Request) {
 		workers = req.Concurrent
 	}
 
+	perHost := s.config.MaxPerHost
+	if req.PerHost > 0 && req.PerHost <= 20 {
+		perHost = req.PerHost
+	}
+
 	delay := s.config.RateLimit
 	if req.RateLimit != "" {
 		if d, err := time.ParseDuration(req.RateLimit); err == nil {
@@ -153,13 +251,14 @@ func (s *Server) BatchScrapeHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	batchResult := s.runBatchScraping(req.URLs, options, workers, delay)
+	limiter := scraper.NewHostLimiter(perHost, delay)
+	batchResult := s.runBatchScraping(req.URLs, options, workers, limiter)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(batchResult)
 } 
 
-func (s *Server) runBatchScraping(urls []string, options types.ScrapeOptions, workers int, delay time.Duration) types.BatchScrapedResult {
+func (s *Server) runBatchScraping(urls []string, options types.ScrapeOptions, workers int, limiter *scraper.HostLimiter) types.BatchScrapedResult {
 	startTime := time.Now()
 	
 	urlChan := make(chan string, len(urls))
@@ -176,12 +275,11 @@ func (s *Server) runBatchScraping(urls []string, options types.ScrapeOptions, wo
 		go func() {
 			defer wg.Done()
 			for url := range urlChan {
+				release := limiter.Acquire(url)
 				result := s.scraper.ScrapeURL(url, options)
-				resultChan <- result
+				release()
 
-				if delay > 0 {
-					time.Sleep(delay)
-				}
+				resultChan <- result
 			}
 		}()
 	}
@@ -17,6 +17,7 @@ func (s *Server) Router() *mux.Router {
 
 	r.HandleFunc("/scrape", s.ScrapeHandler).Methods("POST")
 	r.HandleFunc("/scrape/batch", s.BatchScrapeHandler).Methods("POST")
+	r.HandleFunc("/crawl", s.CrawlHandler).Methods("POST")
 
 	r.HandleFunc("/", s.IndexHandler).Methods("GET")
 
@@ -76,6 +77,22 @@ func (s *Server) IndexHandler(w http.ResponseWriter, r *http.Request) {
 					</pre>
 				</div>
 
+				<div class="endpoint">
+					<h3>POST /crawl</h3>
+					<p>Crawl links breadth-first from a URL and return a site map</p>
+					<p>Example payload:</p>
+					<pre>
+						<code>
+							{
+								"url": "https://example.com",
+								"max_depth": 2,
+								"max_pages": 50,
+								"path_prefix": "/docs"
+							}
+						</code>
+					</pre>
+				</div>
+
 				<h2>Test the API</h2>
 				<p>You can test the API using curl:</p>
 				<pre>
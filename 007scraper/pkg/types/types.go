@@ -1,22 +1,40 @@
 package types
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type ScrapeResult struct {
 	URL			string				`json:"url"`
+	FinalURL	string				`json:"final_url,omitempty"`
 	Title		string				`json:"title"`
 	Description	string				`json:"description,omitempty"`
 	Keywords	[]string			`json:"keywords,omitempty"`
 	Links		[]Link				`json:"links,omitempty"`
 	Images		[]Image				`json:"images,omitempty"`
 	Headers		map[string]string	`json:"headers,omitempty"`
+	Records		[]map[string]interface{}	`json:"records,omitempty"`
 	StatusCode	int					`json:"status_code"`
 	Success		bool				`json:"success"`
+	Attempts	int					`json:"attempts"`
 	Error		string				`json:"error,omitempty"`
+	FailureReason	string			`json:"failure_reason,omitempty"`
 	ScrapedAt	time.Time			`json:"scraped_at"`
 	Duration	time.Duration		`json:"duration"`
 }
 
+const (
+	FailureInvalidRequest	= "invalid_request"
+	FailureNetwork			= "network_error"
+	FailureTimeout			= "timeout"
+	FailureRateLimited		= "rate_limited"
+	FailureServerError		= "server_error"
+	FailureHTTPError		= "http_error"
+	FailureParseError		= "parse_error"
+	FailureExtraction		= "extraction_error"
+)
+
 type Link struct {
 	URL	 string	`json:"url"`
 	Text string	`json:"text"`
@@ -37,6 +55,66 @@ type ScrapeOptions struct {
 	IncludeImages	bool				`json:"include_images"`
 	IncludeLinks	bool				`json:"include_links"`
 	CustomeHeaders	map[string]string	`json:"custom_headers,omitempty"`
+	MaxPages		int					`json:"max_pages,omitempty"`
+	AllowedHosts	[]string			`json:"allowed_hosts,omitempty"`
+	PathPrefix		string				`json:"path_prefix,omitempty"`
+	URLPattern		string				`json:"url_pattern,omitempty"`
+	IgnoreRobots	bool				`json:"ignore_robots,omitempty"`
+	Schema			*Schema				`json:"schema,omitempty"`
+}
+
+// Schema describes structured records to extract from a page. With a
+// selector or xpath every match becomes a record, otherwise the whole
+// page is a single record.
+type Schema struct {
+	Name		string					`json:"name,omitempty"`
+	Selector	string					`json:"selector,omitempty"`
+	XPath		string					`json:"xpath,omitempty"`
+	Fields		map[string]SchemaField	`json:"fields"`
+}
+
+// SchemaField selects nodes by CSS selector or XPath relative to the
+// enclosing record and reads their text, inner html or an attribute.
+// A field written as a plain string is shorthand for its selector.
+type SchemaField struct {
+	Selector	string					`json:"selector,omitempty"`
+	XPath		string					`json:"xpath,omitempty"`
+	Attr		string					`json:"attr,omitempty"`
+	Regex		string					`json:"regex,omitempty"`
+	Type		string					`json:"type,omitempty"`
+	Multiple	bool					`json:"multiple,omitempty"`
+	Fields		map[string]SchemaField	`json:"fields,omitempty"`
+}
+
+func (f *SchemaField) UnmarshalJSON(data []byte) error {
+	var selector string
+	if err := json.Unmarshal(data, &selector); err == nil {
+		*f = SchemaField{Selector: selector}
+		return nil
+	}
+
+	type field SchemaField
+	return json.Unmarshal(data, (*field)(f))
+}
+
+type Page struct {
+	URL		string			`json:"url"`
+	Depth	int				`json:"depth"`
+	Parent	string			`json:"parent,omitempty"`
+	Result	ScrapeResult	`json:"result"`
+}
+
+type SiteMap struct {
+	Root		string			`json:"root"`
+	Pages		[]Page			`json:"pages"`
+	Disallowed	[]string		`json:"disallowed,omitempty"`
+	Total		int				`json:"total"`
+	Success		int				`json:"success"`
+	Failed		int				`json:"failed"`
+	MaxDepth	int				`json:"max_depth"`
+	StartTime	time.Time		`json:"start_time"`
+	EndTime		time.Time		`json:"end_time"`
+	Duration	time.Duration	`json:"duration"`
 }
 
 type BatchScrapedResult struct {
@@ -55,6 +133,7 @@ func DefaultScrapeOptions() ScrapeOptions {
 		UserAgent: "Go-web-scraper/1.0",
 		FollowLinks: false,
 		MaxDepth: 1,
+		MaxPages: 100,
 		IncludeImages: true,
 		IncludeLinks: true,
 		CustomeHeaders: make(map[string]string),